@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"grua/internal/checks"
+	"grua/internal/git"
+)
+
+// runCheck checks the uncommitted changes of the repository at repoPath
+// and prints the findings, one per line, tuned by rules. Suppressed
+// findings follow under their own heading, indented so that tools reading
+// file:line positions skip them, with the reason each was waived. It
+// returns the exit status: 1 if any finding that is not suppressed is high
+// severity, 2 if the checks could not run.
+func runCheck(repoPath string, rules checks.Rules) int {
+	service := git.NewService(repoPath)
+	files, err := service.GetChangedFiles()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading changes: %v\n", err)
+		return 2
+	}
+	changes, err := checks.CheckChanges(service, repoPath, files, rules)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running checks: %v\n", err)
+		return 2
+	}
+
+	status, n := 0, 0
+	var suppressed []checks.Finding
+	for _, f := range changes.Findings {
+		if f.Suppressed {
+			suppressed = append(suppressed, f)
+			continue
+		}
+		n++
+		fmt.Println(formatFinding(f))
+		if f.Severity == checks.SeverityHigh {
+			status = 1
+		}
+	}
+	if len(suppressed) > 0 {
+		fmt.Println("suppressed:")
+		for _, f := range suppressed {
+			reason := f.Reason
+			if reason == "" {
+				reason = "no reason given"
+			}
+			fmt.Printf("  %s (%s)\n", formatFinding(f), reason)
+		}
+	}
+	var summary string
+	switch n {
+	case 0:
+		summary = "No findings"
+	case 1:
+		summary = "1 finding"
+	default:
+		summary = fmt.Sprintf("%d findings", n)
+	}
+	if len(suppressed) > 0 {
+		summary += fmt.Sprintf(", %d suppressed", len(suppressed))
+	}
+	fmt.Fprintln(os.Stderr, summary)
+	return status
+}
+
+// formatFinding renders a finding in the file:line:column form editors
+// and CI logs recognise.
+func formatFinding(f checks.Finding) string {
+	pos := f.Path
+	if f.Line > 0 {
+		pos += fmt.Sprintf(":%d", f.Line)
+		if f.Column > 0 {
+			pos += fmt.Sprintf(":%d", f.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s/%s: %s", pos, f.Severity, f.Source, f.Rule, f.Message)
+}
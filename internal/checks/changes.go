@@ -0,0 +1,160 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"grua/internal/git"
+)
+
+// Changes is the result of checking a repository's uncommitted changes.
+type Changes struct {
+	Findings []Finding
+	// Added holds the lines each changed file gained since HEAD.
+	Added map[string][]git.LineRange
+}
+
+// Targets returns the changed files worth checking, once per path: those
+// with a working tree version, and deleted files, whose packages may no
+// longer build.
+func Targets(files []git.FileStatus) []git.FileStatus {
+	var targets []git.FileStatus
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if file.Commit != "" || file.Unmerged || file.Submodule != "" || seen[file.Path] {
+			continue
+		}
+		seen[file.Path] = true
+		targets = append(targets, file)
+	}
+	return targets
+}
+
+// CheckChanges runs the checks over the changed files of the repository
+// at dir, and scans the other changed files for secrets. Analysis findings
+// are kept on added lines only; findings about the change itself, such as
+// compile errors and weakened tests, are kept wherever they are. rules
+// then tunes the findings, and ignore comments on added lines suppress
+// them.
+func CheckChanges(service *git.Service, dir string, files []git.FileStatus, rules Rules) (*Changes, error) {
+	targets := Targets(files)
+	changes := &Changes{Added: make(map[string][]git.LineRange)}
+	var paths []string
+	for _, file := range targets {
+		if strings.HasSuffix(file.Path, ".go") {
+			paths = append(paths, file.Path)
+		}
+		if file.Deleted {
+			continue
+		}
+		ranges, err := service.GetAddedLines(file)
+		if err != nil {
+			return nil, err
+		}
+		changes.Added[file.Path] = ranges
+	}
+
+	findings, err := Check(dir, paths)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range targets {
+		findings = append(findings, checkFile(service, dir, file)...)
+	}
+	findings = OnAddedLines(findings, changes.Added)
+
+	secrets, err := scanChanges(service, dir, changes.Added)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, secrets...)
+
+	for _, file := range targets {
+		if strings.HasSuffix(file.Path, "_test.go") && !file.Unversioned {
+			if oldSrc := HeadVersion(service, file); oldSrc != nil {
+				findings = append(findings, WeakenedTests(file.Path, oldSrc, readFile(dir, file))...)
+			}
+		}
+	}
+	findings, err = Suppress(dir, rules.Apply(findings), changes.Added)
+	if err != nil {
+		return nil, err
+	}
+	changes.Findings = sortFindings(findings)
+	return changes, nil
+}
+
+// scanChanges scans the lines added to every changed file, not only Go
+// files, for secrets. added holds the added lines already known, and
+// gains those of the other files.
+func scanChanges(service *git.Service, dir string, added map[string][]git.LineRange) ([]Finding, error) {
+	files, err := service.GetAllChangedFiles()
+	if err != nil {
+		return nil, err
+	}
+	allow, err := LoadAllowlist(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, file := range Targets(files) {
+		src := readFile(dir, file)
+		if src == nil {
+			continue
+		}
+		ranges, ok := added[file.Path]
+		if !ok {
+			if ranges, err = service.GetAddedLines(file); err != nil {
+				return nil, err
+			}
+			added[file.Path] = ranges
+		}
+		findings = append(findings, OnAddedLines(ScanSecrets(file.Path, src, allow),
+			map[string][]git.LineRange{file.Path: ranges})...)
+	}
+	return findings, nil
+}
+
+// checkFile runs the checks that look at a single Go file's old and new
+// versions.
+func checkFile(service *git.Service, dir string, file git.FileStatus) []Finding {
+	if file.Deleted || !strings.HasSuffix(file.Path, ".go") || strings.HasSuffix(file.Path, "_test.go") {
+		return nil
+	}
+	newSrc := readFile(dir, file)
+	if newSrc == nil {
+		return nil
+	}
+	return MissingDocs(file.Path, HeadVersion(service, file), newSrc)
+}
+
+// HeadVersion returns a changed file as of HEAD, or nil if it is new.
+func HeadVersion(service *git.Service, file git.FileStatus) []byte {
+	if file.Unversioned {
+		return nil
+	}
+	path := file.Path
+	if file.OldPath != "" {
+		path = file.OldPath
+	}
+	lines, err := service.GetRevisionContent("HEAD", path)
+	if err != nil {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// readFile returns the working tree version of a file, or nil if it was
+// deleted.
+func readFile(dir string, file git.FileStatus) []byte {
+	if file.Deleted {
+		return nil
+	}
+	src, err := os.ReadFile(filepath.Join(dir, file.Path))
+	if err != nil {
+		return nil
+	}
+	return src
+}
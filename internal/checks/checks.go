@@ -0,0 +1,27 @@
+package checks
+
+// Check loads the packages containing files, given relative to the
+// repository root dir, and runs the checks over them. Findings are
+// returned sorted by position; only compile errors may lie outside files.
+func Check(dir string, files []string) ([]Finding, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	pkgs, err := load(dir, files)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(files))
+	for _, file := range files {
+		wanted[file] = true
+	}
+
+	findings := compileErrors(dir, pkgs, wanted)
+	vetFindings, err := runVet(dir, pkgs, wanted)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, vetFindings...)
+	return sortFindings(findings), nil
+}
@@ -0,0 +1,81 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeModule writes files into a new module in a temporary directory and
+// returns its path.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/m\n\ngo 1.22\n"
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCheckVet(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": `package a
+
+import "fmt"
+
+func F(n int) {
+	fmt.Printf("%s\n", n)
+}
+`,
+		"a/b.go": `package a
+
+import "fmt"
+
+func G() {
+	fmt.Printf("%d\n", "x")
+}
+`,
+		"a/a_test.go": `package a
+
+import "testing"
+
+func TestF(t *testing.T) {
+	x := 1
+	if true {
+		x := 2
+		_ = x
+	}
+	_ = x
+}
+`,
+	})
+
+	findings, err := Check(dir, []string{"a/a.go", "a/a_test.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Path+" "+f.Source+"/"+f.Rule+" "+f.Severity.String())
+	}
+	// b.go is unchanged, and a.go is checked once although it is in both
+	// the package and its test variant.
+	want := []string{"a/a.go vet/printf medium", "a/a_test.go vet/shadow low"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Check = %q, want %q", got, want)
+	}
+	if findings[0].Line != 6 || findings[0].Column != 14 {
+		t.Errorf("printf finding at %d:%d, want 6:14", findings[0].Line, findings[0].Column)
+	}
+
+	if findings, err := Check(dir, nil); err != nil || findings != nil {
+		t.Errorf("no files: Check = %+v, %v", findings, err)
+	}
+}
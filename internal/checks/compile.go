@@ -0,0 +1,114 @@
+package checks
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// sourceCompile is the Source of findings for parse and type errors.
+const sourceCompile = "compile"
+
+// compileErrors returns the errors from loading pkgs, positioned where
+// they occur. Errors in every file of the packages are returned, since a
+// change often breaks code it did not touch, such as the callers of a
+// function whose signature changed. Errors without a position, such as
+// import cycles and module errors from go list, are reported on the
+// package's changed files, or on its first file if none changed.
+func compileErrors(dir string, pkgs []*packages.Package, wanted map[string]bool) []Finding {
+	var findings []Finding
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			finding := Finding{
+				Source:   sourceCompile,
+				Rule:     errorRule(e.Kind),
+				Severity: SeverityHigh,
+				Message:  oneLine(e.Msg),
+			}
+			file, line, col := splitPos(e.Pos)
+			if !filepath.IsAbs(file) {
+				for _, path := range packageFiles(dir, pkg, wanted) {
+					finding.Path = path
+					findings = append(findings, finding)
+				}
+				continue
+			}
+			if finding.Path = relPath(dir, file); finding.Path == "" {
+				continue
+			}
+			finding.Line, finding.Column = line, col
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// packageFiles returns the changed files of pkg, or its first file if none
+// of them changed.
+func packageFiles(dir string, pkg *packages.Package, wanted map[string]bool) []string {
+	var changed, all []string
+	for _, file := range pkg.GoFiles {
+		path := relPath(dir, file)
+		if path == "" {
+			continue
+		}
+		all = append(all, path)
+		if wanted[path] {
+			changed = append(changed, path)
+		}
+	}
+	if len(changed) == 0 && len(all) > 0 {
+		return all[:1]
+	}
+	return changed
+}
+
+// IsCompileError reports whether a finding is a parse or type error.
+func (f Finding) IsCompileError() bool {
+	return f.Source == sourceCompile
+}
+
+// oneLine joins the lines of a multi-line error message, such as the
+// have and want lines of a call with the wrong arguments.
+func oneLine(msg string) string {
+	return strings.Join(strings.Fields(msg), " ")
+}
+
+func errorRule(kind packages.ErrorKind) string {
+	switch kind {
+	case packages.ParseError:
+		return "parse"
+	case packages.TypeError:
+		return "type"
+	default:
+		return "build"
+	}
+}
+
+// splitPos splits an error position of the form file:line:col, where
+// the line and column are optional.
+func splitPos(pos string) (file string, line, col int) {
+	file = pos
+	var nums []int
+	for len(nums) < 2 {
+		i := strings.LastIndexByte(file, ':')
+		if i < 0 {
+			break
+		}
+		n, err := strconv.Atoi(file[i+1:])
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+		file = file[:i]
+	}
+	switch len(nums) {
+	case 1:
+		line = nums[0]
+	case 2:
+		line, col = nums[1], nums[0]
+	}
+	return file, line, col
+}
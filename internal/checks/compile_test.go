@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitPos(t *testing.T) {
+	tests := []struct {
+		pos       string
+		file      string
+		line, col int
+	}{
+		{"/r/a.go:12:5", "/r/a.go", 12, 5},
+		{"/r/a.go:12", "/r/a.go", 12, 0},
+		{"/r/a.go", "/r/a.go", 0, 0},
+		{"C:/r/a.go:3:1", "C:/r/a.go", 3, 1},
+		{"-", "-", 0, 0},
+		{"", "", 0, 0},
+	}
+	for _, tt := range tests {
+		file, line, col := splitPos(tt.pos)
+		if file != tt.file || line != tt.line || col != tt.col {
+			t.Errorf("splitPos(%q) = %q, %d, %d, want %q, %d, %d", tt.pos, file, line, col, tt.file, tt.line, tt.col)
+		}
+	}
+}
+
+func TestCheckCompileErrors(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go":   "package a\n\nfunc F(n int, s string) int { return n }\n",
+		"a/use.go": "package a\n\nvar _ = F(1)\n",
+		"b/b.go":   "package b\n\nfunc G() {}}\n",
+	})
+
+	findings, err := Check(dir, []string{"a/a.go", "b/b.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range findings {
+		if !f.IsCompileError() || f.Severity != SeverityHigh {
+			t.Errorf("finding %+v is not a high compile error", f)
+		}
+		got = append(got, f.Path+" "+f.Rule)
+	}
+	// The caller in use.go broke although only a.go changed.
+	want := []string{"a/use.go type", "b/b.go parse"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Check = %q, want %q", got, want)
+	}
+	if f := findings[0]; f.Line != 3 || f.Column == 0 {
+		t.Errorf("type error at %d:%d, want line 3", f.Line, f.Column)
+	}
+	if msg := findings[0].Message; msg == "" || strings.Contains(msg, "\n") {
+		t.Errorf("message %q is not one line", msg)
+	}
+}
+
+func TestCheckDeletedFile(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/use.go": "package a\n\nvar _ = F()\n",
+	})
+	findings, err := Check(dir, []string{"a/a.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Path != "a/use.go" || findings[0].Rule != "type" {
+		t.Errorf("Check = %+v, want the now undefined F in use.go", findings)
+	}
+}
+
+func TestCheckErrorWithoutPosition(t *testing.T) {
+	// go list reports import cycles on the package, not on a line.
+	dir := writeModule(t, map[string]string{
+		"b/b.go":      "package b\n\nimport _ \"example.com/m/c\"\n",
+		"b/helper.go": "package b\n",
+		"c/c.go":      "package c\n\nimport _ \"example.com/m/b\"\n",
+	})
+	findings, err := Check(dir, []string{"b/b.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("Check = %+v, want one finding", findings)
+	}
+	if f := findings[0]; f.Path != "b/b.go" || f.Line != 0 || !f.IsCompileError() || !strings.Contains(f.Message, "import cycle") {
+		t.Errorf("finding = %+v, want the import cycle on b/b.go", f)
+	}
+}
+
+func TestCheckNestedModule(t *testing.T) {
+	// tools is a module of its own, so it is loaded from its root.
+	dir := writeModule(t, map[string]string{
+		"a/a.go":       "package a\n",
+		"tools/go.mod": "module example.com/tools\n\ngo 1.22\n",
+		"tools/t/t.go": "package t\n\nimport \"example.com/tools/u\"\n\nvar _ int = u.S\n",
+		"tools/u/u.go": "package u\n\nvar S = \"s\"\n",
+	})
+	findings, err := Check(dir, []string{"a/a.go", "tools/t/t.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Path != "tools/t/t.go" || findings[0].Rule != "type" || findings[0].Line != 5 {
+		t.Errorf("Check = %+v, want the type error in tools/t/t.go", findings)
+	}
+}
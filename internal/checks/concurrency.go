@@ -0,0 +1,244 @@
+package checks
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"go/version"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Categories of concurrency diagnostics, used as finding rules. Copied
+// locks, WaitGroup.Add inside goroutines and loop variables captured by a
+// loop's last statement are found by go vet and filed under them too.
+const (
+	ruleLoopCapture   = "loop-capture"
+	ruleWaitGroupAdd  = "waitgroup-add"
+	ruleBlockedSend   = "blocked-send"
+	ruleSleepSync     = "sleep-sync"
+	ruleCopiedLock    = "copied-lock"
+	sourceConcurrency = "concurrency"
+)
+
+// concurrencyAnalyzer flags common goroutine mistakes: capturing loop
+// variables before Go 1.22 anywhere in the loop, sending on an unbuffered
+// channel nothing receives from, and waiting for goroutines with
+// time.Sleep.
+var concurrencyAnalyzer = &analysis.Analyzer{
+	Name:     "concurrency",
+	Doc:      "report goroutine and channel hazards",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runConcurrency,
+}
+
+func runConcurrency(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.WithStack([]ast.Node{(*ast.GoStmt)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		stmt := n.(*ast.GoStmt)
+		lit, ok := stmt.Call.Fun.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		if sharesLoopVars(pass, stack) {
+			checkLoopCapture(pass, lit, stack)
+		}
+		return true
+	})
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			body = n.Body
+		case *ast.FuncLit:
+			body = n.Body
+		}
+		if body == nil {
+			return
+		}
+		checkBlockedSends(pass, body)
+		checkSleepSync(pass, body)
+	})
+	return nil, nil
+}
+
+// sharesLoopVars reports whether the file at the top of stack predates
+// Go 1.22, when each loop iteration got its own variables.
+func sharesLoopVars(pass *analysis.Pass, stack []ast.Node) bool {
+	file, ok := stack[0].(*ast.File)
+	if !ok {
+		return false
+	}
+	v := pass.TypesInfo.FileVersions[file]
+	if v == "" {
+		v = pass.Pkg.GoVersion()
+	}
+	return v != "" && version.Compare(v, "go1.22") < 0
+}
+
+// checkLoopCapture reports loop variables of enclosing loops that a
+// goroutine's function literal uses instead of taking as arguments.
+func checkLoopCapture(pass *analysis.Pass, lit *ast.FuncLit, stack []ast.Node) {
+	loopVars := make(map[types.Object]bool)
+	for _, n := range stack {
+		switch loop := n.(type) {
+		case *ast.RangeStmt:
+			if loop.Tok == token.DEFINE {
+				for _, e := range []ast.Expr{loop.Key, loop.Value} {
+					if ident, ok := e.(*ast.Ident); ok {
+						loopVars[pass.TypesInfo.Defs[ident]] = true
+					}
+				}
+			}
+		case *ast.ForStmt:
+			if init, ok := loop.Init.(*ast.AssignStmt); ok && init.Tok == token.DEFINE {
+				for _, e := range init.Lhs {
+					if ident, ok := e.(*ast.Ident); ok {
+						loopVars[pass.TypesInfo.Defs[ident]] = true
+					}
+				}
+			}
+		case *ast.FuncLit:
+			// Loops outside an enclosing function literal run before it.
+			clear(loopVars)
+		}
+	}
+	reported := make(map[types.Object]bool)
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		obj := pass.TypesInfo.Uses[ident]
+		if obj != nil && loopVars[obj] && !reported[obj] {
+			reported[obj] = true
+			pass.Report(analysis.Diagnostic{
+				Pos:      ident.Pos(),
+				Category: ruleLoopCapture,
+				Message:  "goroutine captures loop variable " + ident.Name + ", which every iteration shares before Go 1.22; pass it as an argument",
+			})
+		}
+		return true
+	})
+}
+
+// checkBlockedSends reports sends on unbuffered channels made in body that
+// nothing in body receives from and that are not handed elsewhere, so the
+// send can never complete.
+func checkBlockedSends(pass *analysis.Pass, body *ast.BlockStmt) {
+	chans := make(map[types.Object]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return n.Body == body
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+				for i, rhs := range n.Rhs {
+					if ident, ok := n.Lhs[i].(*ast.Ident); ok && isUnbufferedMake(pass, rhs) {
+						chans[pass.TypesInfo.Defs[ident]] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) == len(n.Values) {
+				for i, value := range n.Values {
+					if isUnbufferedMake(pass, value) {
+						chans[pass.TypesInfo.Defs[n.Names[i]]] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	if len(chans) == 0 {
+		return
+	}
+
+	// Classify every use of the channels, in nested function literals too.
+	// Receiving, ranging, closing or passing a channel on all give its
+	// sends a way to complete.
+	sends := make(map[types.Object][]*ast.SendStmt)
+	handled := make(map[types.Object]bool)
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if ident, ok := n.(*ast.Ident); ok {
+			if obj := pass.TypesInfo.Uses[ident]; chans[obj] {
+				if send, ok := stack[len(stack)-1].(*ast.SendStmt); ok && send.Chan == ident {
+					sends[obj] = append(sends[obj], send)
+				} else {
+					handled[obj] = true
+				}
+			}
+		}
+		stack = append(stack, n)
+		return true
+	})
+
+	for obj, objSends := range sends {
+		if handled[obj] {
+			continue
+		}
+		for _, send := range objSends {
+			pass.Report(analysis.Diagnostic{
+				Pos:      send.Pos(),
+				Category: ruleBlockedSend,
+				Message:  "send on unbuffered channel " + obj.Name() + " blocks forever: nothing receives from it",
+			})
+		}
+	}
+}
+
+// isUnbufferedMake reports whether expr is make(chan T) or make(chan T, 0).
+func isUnbufferedMake(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	if b, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Builtin); !ok || b.Name() != "make" {
+		return false
+	}
+	if _, ok := pass.TypesInfo.TypeOf(call.Args[0]).Underlying().(*types.Chan); !ok {
+		return false
+	}
+	if len(call.Args) == 1 {
+		return true
+	}
+	size := pass.TypesInfo.Types[call.Args[1]].Value
+	return size != nil && constant.Sign(size) == 0
+}
+
+// checkSleepSync reports time.Sleep in a function that starts goroutines,
+// after the first go statement: sleeping hopes the goroutines finish
+// rather than waiting for them.
+func checkSleepSync(pass *analysis.Pass, body *ast.BlockStmt) {
+	var started bool
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.GoStmt:
+			started = true
+			return false
+		case *ast.CallExpr:
+			if started && isFunc(pass, n, "time", "Sleep") {
+				pass.Report(analysis.Diagnostic{
+					Pos:      n.Pos(),
+					Category: ruleSleepSync,
+					Message:  "time.Sleep used to wait for goroutines; use a WaitGroup or channel instead",
+				})
+			}
+		}
+		return true
+	})
+}
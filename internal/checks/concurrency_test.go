@@ -0,0 +1,11 @@
+package checks
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestConcurrencyAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), concurrencyAnalyzer, "conc")
+}
@@ -0,0 +1,168 @@
+package checks
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// sourceDocs is the Source of findings about doc comments.
+const sourceDocs = "docs"
+
+// exportedDecl is an exported package-level identifier and its doc
+// comment.
+type exportedDecl struct {
+	name string
+	kind string
+	pos  token.Pos
+	doc  *ast.CommentGroup
+	// grouped is set for specs in a parenthesised declaration, which may
+	// be documented by the group's comment instead.
+	grouped bool
+}
+
+// MissingDocs reports the exported functions, methods, types and
+// variables that a change added to a file without a doc comment, or with
+// one that does not start with the identifier's name. oldSrc is nil for a
+// new file.
+func MissingDocs(path string, oldSrc, newSrc []byte) []Finding {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, newSrc, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	existing := make(map[string]bool)
+	if oldSrc != nil {
+		oldFile, err := parser.ParseFile(token.NewFileSet(), path, oldSrc, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil
+		}
+		for _, decl := range exportedDecls(oldFile) {
+			existing[decl.name] = true
+		}
+	}
+
+	var findings []Finding
+	for _, decl := range exportedDecls(file) {
+		if existing[decl.name] {
+			continue
+		}
+		rule, message := "", ""
+		switch {
+		case decl.doc == nil && !decl.grouped:
+			rule, message = "missing-doc", "exported "+decl.kind+" "+decl.name+" has no doc comment"
+		case decl.doc != nil && !decl.grouped && !docNamesIdent(decl.doc.Text(), decl.name):
+			rule, message = "doc-name", "doc comment for "+decl.name+" should start with its name"
+		default:
+			continue
+		}
+		pos := fset.Position(decl.pos)
+		findings = append(findings, Finding{
+			Path:     path,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Source:   sourceDocs,
+			Rule:     rule,
+			Severity: SeverityLow,
+			Message:  message,
+		})
+	}
+	return findings
+}
+
+// exportedDecls lists the exported identifiers declared at package level,
+// naming methods after their receiver type as in T.Method. Methods of
+// unexported types are left out, as they are not part of the API.
+func exportedDecls(file *ast.File) []exportedDecl {
+	var decls []exportedDecl
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			name, kind := d.Name.Name, "function"
+			if d.Recv != nil {
+				recv := receiverType(d.Recv)
+				if !ast.IsExported(recv) {
+					continue
+				}
+				name, kind = recv+"."+name, "method"
+			}
+			decls = append(decls, exportedDecl{name: name, kind: kind, pos: d.Name.Pos(), doc: d.Doc})
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE && d.Tok != token.VAR {
+				continue
+			}
+			grouped := d.Lparen.IsValid() && d.Doc != nil
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					doc := s.Doc
+					if doc == nil && !d.Lparen.IsValid() {
+						doc = d.Doc
+					}
+					if s.Name.IsExported() {
+						decls = append(decls, exportedDecl{name: s.Name.Name, kind: "type", pos: s.Name.Pos(), doc: doc, grouped: grouped && doc == nil})
+					}
+				case *ast.ValueSpec:
+					doc := s.Doc
+					if doc == nil && !d.Lparen.IsValid() {
+						doc = d.Doc
+					}
+					for _, ident := range s.Names {
+						if ident.IsExported() {
+							decls = append(decls, exportedDecl{name: ident.Name, kind: "variable", pos: ident.Pos(), doc: doc, grouped: grouped && doc == nil})
+						}
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// receiverType returns the name of a method's receiver type.
+func receiverType(recv *ast.FieldList) string {
+	if len(recv.List) == 0 {
+		return ""
+	}
+	typ := recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// docNamesIdent reports whether doc starts with the identifier's name,
+// after an optional article. For methods the method name alone will do.
+func docNamesIdent(doc, name string) bool {
+	if _, method, ok := strings.Cut(name, "."); ok {
+		name = method
+	}
+	for _, article := range []string{"", "A ", "An ", "The "} {
+		rest, ok := strings.CutPrefix(doc, article)
+		if ok && strings.HasPrefix(rest, name) {
+			after := strings.TrimPrefix(rest, name)
+			if after == "" || !isIdentRune(after[0]) {
+				return true
+			}
+		}
+	}
+	// Deprecation notices may stand alone.
+	return strings.HasPrefix(doc, "Deprecated:")
+}
+
+func isIdentRune(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,121 @@
+package checks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingDocs(t *testing.T) {
+	oldSrc := []byte(`package p
+
+func Old() {}
+`)
+	newSrc := []byte(`package p
+
+func Old() {}
+
+func New() {}
+
+// Documented does nothing.
+func Documented() {}
+
+// does nothing either.
+func Misnamed() {}
+
+// A Thing is a thing.
+type Thing struct{}
+
+func (Thing) Method() {}
+
+// Method2 has a doc comment.
+func (*Thing) Method2() {}
+
+type hidden struct{}
+
+func (hidden) Exported() {}
+
+func unexported() {}
+
+// Deprecated: use New.
+func Legacy() {}
+
+// Generic is generic.
+type Generic[T any] struct{}
+
+func (Generic[T]) Get() {}
+
+// Limits are documented as a group.
+var (
+	Max = 1
+	Min = 0
+)
+
+var (
+	// Debug enables logging.
+	Debug bool
+	Quiet bool
+)
+
+var Single, single = 1, 2
+
+// Options configures things.
+type (
+	Options struct{}
+)
+
+// Newer is not named by New.
+func NewerThing() {}
+
+const Untracked = 1
+`)
+	var got []string
+	for _, f := range MissingDocs("p.go", oldSrc, newSrc) {
+		got = append(got, f.Rule+" "+f.Message)
+		if f.Source != sourceDocs || f.Severity != SeverityLow || f.Line == 0 {
+			t.Errorf("finding %+v", f)
+		}
+	}
+	want := []string{
+		"missing-doc exported function New has no doc comment",
+		"doc-name doc comment for Misnamed should start with its name",
+		"missing-doc exported method Thing.Method has no doc comment",
+		"missing-doc exported method Generic.Get has no doc comment",
+		"missing-doc exported variable Quiet has no doc comment",
+		"missing-doc exported variable Single has no doc comment",
+		"doc-name doc comment for NewerThing should start with its name",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingDocs =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestMissingDocsNewFile(t *testing.T) {
+	findings := MissingDocs("p.go", nil, []byte("package p\n\nfunc A() {}\n"))
+	if len(findings) != 1 || findings[0].Line != 3 || findings[0].Column != 6 {
+		t.Errorf("findings = %+v, want A at 3:6", findings)
+	}
+	if findings := MissingDocs("p.go", nil, []byte("package p\n\nfunc A() {")); findings != nil {
+		t.Errorf("syntax error: findings = %+v, want none", findings)
+	}
+}
+
+func TestDocNamesIdent(t *testing.T) {
+	tests := []struct {
+		doc, name string
+		want      bool
+	}{
+		{"Run runs.", "Run", true},
+		{"Runner runs.", "Run", false},
+		{"An Error is an error.", "Error", true},
+		{"The T type.", "T", true},
+		{"Get returns the value.", "Cache.Get", true},
+		{"Deprecated: gone.", "Old", true},
+		{"run runs.", "Run", false},
+		{"Run", "Run", true},
+	}
+	for _, tt := range tests {
+		if got := docNamesIdent(tt.doc, tt.name); got != tt.want {
+			t.Errorf("docNamesIdent(%q, %q) = %v, want %v", tt.doc, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package checks
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Categories of error-handling diagnostics, used as finding rules.
+const (
+	ruleIgnoredError   = "ignored-error"
+	ruleDroppedError   = "dropped-error"
+	ruleUnwrappedError = "unwrapped-error"
+	ruleLogFatal       = "log-fatal"
+)
+
+// errorsAnalyzer audits error handling: ignored error results, errors
+// checked and then dropped, fmt.Errorf wrapping without %w, and log.Fatal
+// outside main packages.
+var errorsAnalyzer = &analysis.Analyzer{
+	Name:     "errors",
+	Doc:      "report doubtful error handling",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runErrors,
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func runErrors(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodes := []ast.Node{(*ast.ExprStmt)(nil), (*ast.AssignStmt)(nil), (*ast.IfStmt)(nil), (*ast.CallExpr)(nil)}
+	insp.Preorder(nodes, func(n ast.Node) {
+		if strings.HasSuffix(pass.Fset.Position(n.Pos()).Filename, "_test.go") {
+			return
+		}
+		switch n := n.(type) {
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok && returnsError(pass, call) && !ignorable(pass, call) {
+				pass.Report(analysis.Diagnostic{
+					Pos:      call.Pos(),
+					Category: ruleIgnoredError,
+					Message:  "error result of " + calleeName(pass, call) + " is not checked",
+				})
+			}
+		case *ast.AssignStmt:
+			checkBlankError(pass, n)
+		case *ast.IfStmt:
+			checkDroppedError(pass, n)
+		case *ast.CallExpr:
+			checkErrorf(pass, n)
+			checkLogFatal(pass, n)
+		}
+	})
+	return nil, nil
+}
+
+// checkBlankError reports errors assigned to the blank identifier.
+func checkBlankError(pass *analysis.Pass, assign *ast.AssignStmt) {
+	if len(assign.Rhs) != 1 {
+		return
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	results := resultTypes(pass, call)
+	if len(results) != len(assign.Lhs) {
+		return
+	}
+	for i, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && ident.Name == "_" && types.Identical(results[i], errorType) {
+			pass.Report(analysis.Diagnostic{
+				Pos:      ident.Pos(),
+				Category: ruleIgnoredError,
+				Message:  "error result of " + calleeName(pass, call) + " is discarded",
+			})
+		}
+	}
+}
+
+// checkDroppedError reports if err != nil blocks that neither use the
+// error nor pass it on, so the cause is lost.
+func checkDroppedError(pass *analysis.Pass, stmt *ast.IfStmt) {
+	cond, ok := stmt.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.NEQ || !isNil(pass, cond.Y) {
+		return
+	}
+	ident, ok := cond.X.(*ast.Ident)
+	if !ok || !types.Identical(pass.TypesInfo.TypeOf(ident), errorType) {
+		return
+	}
+	obj := pass.TypesInfo.Uses[ident]
+	used := false
+	ast.Inspect(stmt.Body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == obj {
+			used = true
+		}
+		return !used
+	})
+	if !used {
+		pass.Report(analysis.Diagnostic{
+			Pos:      ident.Pos(),
+			Category: ruleDroppedError,
+			Message:  ident.Name + " is checked but neither returned, wrapped nor logged",
+		})
+	}
+}
+
+// checkErrorf reports fmt.Errorf calls that format an error without %w,
+// which hides it from errors.Is and errors.As.
+func checkErrorf(pass *analysis.Pass, call *ast.CallExpr) {
+	if !isFunc(pass, call, "fmt", "Errorf") || len(call.Args) < 2 {
+		return
+	}
+	format := pass.TypesInfo.Types[call.Args[0]].Value
+	if format == nil || format.Kind() != constant.String || strings.Contains(constant.StringVal(format), "%w") {
+		return
+	}
+	for _, arg := range call.Args[1:] {
+		if types.Identical(pass.TypesInfo.TypeOf(arg), errorType) {
+			pass.Report(analysis.Diagnostic{
+				Pos:      call.Pos(),
+				Category: ruleUnwrappedError,
+				Message:  "fmt.Errorf formats an error without %w, so callers cannot unwrap it",
+			})
+			return
+		}
+	}
+}
+
+// checkLogFatal reports log.Fatal outside main packages, where it exits
+// the program without giving the caller a say.
+func checkLogFatal(pass *analysis.Pass, call *ast.CallExpr) {
+	if pass.Pkg.Name() == "main" {
+		return
+	}
+	for _, name := range []string{"Fatal", "Fatalf", "Fatalln"} {
+		if isFunc(pass, call, "log", name) {
+			pass.Report(analysis.Diagnostic{
+				Pos:      call.Pos(),
+				Category: ruleLogFatal,
+				Message:  "log." + name + " in a library package exits the program; return an error instead",
+			})
+			return
+		}
+	}
+}
+
+// resultTypes returns the result types of a call, or nil for conversions
+// and builtins.
+func resultTypes(pass *analysis.Pass, call *ast.CallExpr) []types.Type {
+	t := pass.TypesInfo.TypeOf(call.Fun)
+	if t == nil {
+		return nil
+	}
+	sig, ok := t.Underlying().(*types.Signature)
+	if !ok {
+		return nil
+	}
+	var results []types.Type
+	for v := range sig.Results().Variables() {
+		results = append(results, v.Type())
+	}
+	return results
+}
+
+func returnsError(pass *analysis.Pass, call *ast.CallExpr) bool {
+	for _, t := range resultTypes(pass, call) {
+		if types.Identical(t, errorType) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignorable reports whether a call's error is customarily ignored: printing
+// with fmt, and writes to in-memory buffers and hashes, which never fail.
+func ignorable(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return false
+	}
+	if fn.Pkg().Path() == "fmt" && (strings.HasPrefix(fn.Name(), "Print") || strings.HasPrefix(fn.Name(), "Fprint")) {
+		return true
+	}
+	// Methods are matched by the type they are called on, since a write to
+	// a hash.Hash calls the io.Writer method it embeds.
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || fn.Signature().Recv() == nil {
+		return false
+	}
+	t := pass.TypesInfo.TypeOf(sel.X)
+	if t == nil {
+		return false
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	switch types.TypeString(t, nil) {
+	case "bytes.Buffer", "strings.Builder", "hash.Hash", "hash.Hash32", "hash.Hash64":
+		return true
+	}
+	return false
+}
+
+// isFunc reports whether call calls the package-level function pkg.name.
+func isFunc(pass *analysis.Pass, call *ast.CallExpr, pkg, name string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == pkg && fn.Name() == name &&
+		fn.Signature().Recv() == nil
+}
+
+func isNil(pass *analysis.Pass, expr ast.Expr) bool {
+	return pass.TypesInfo.Types[expr].IsNil()
+}
+
+// calleeName names the function a call calls, for messages.
+func calleeName(pass *analysis.Pass, call *ast.CallExpr) string {
+	if fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func); ok {
+		if recv := fn.Signature().Recv(); recv != nil {
+			return types.TypeString(recv.Type(), (*types.Package).Name) + "." + fn.Name()
+		}
+		if fn.Pkg() != nil && fn.Pkg() != pass.Pkg {
+			return fn.Pkg().Name() + "." + fn.Name()
+		}
+		return fn.Name()
+	}
+	return "call"
+}
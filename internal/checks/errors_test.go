@@ -0,0 +1,11 @@
+package checks
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestErrorsAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), errorsAnalyzer, "errs")
+}
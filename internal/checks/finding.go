@@ -0,0 +1,89 @@
+// Package checks runs static checks over the Go files touched by a change
+// and reports their findings by file and line.
+package checks
+
+import (
+	"cmp"
+	"slices"
+
+	"grua/internal/git"
+)
+
+// Severity ranks findings.
+type Severity int
+
+const (
+	SeverityLow Severity = iota
+	SeverityMedium
+	SeverityHigh
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// Finding is a problem reported on a line of a changed file.
+type Finding struct {
+	// Path is relative to the repository root.
+	Path string
+	// Line and Column are 1-based positions in the working tree version
+	// of the file. Line is 0 for findings about the whole file.
+	Line   int
+	Column int
+	// Source is the check that produced the finding, and Rule the
+	// specific analyzer or rule within it.
+	Source   string
+	Rule     string
+	Severity Severity
+	Message  string
+	// Suppressed is set when an ignore comment waives the finding, for
+	// the comment's Reason.
+	Suppressed bool
+	Reason     string
+}
+
+// OnAddedLines keeps the findings that fall on lines added by the change.
+// added maps each path to its added line ranges; file-level findings are
+// kept for any file in it. Compile errors are always kept: code that does
+// not build is the change's fault wherever the error shows.
+func OnAddedLines(findings []Finding, added map[string][]git.LineRange) []Finding {
+	var kept []Finding
+	for _, f := range findings {
+		if f.IsCompileError() {
+			kept = append(kept, f)
+			continue
+		}
+		ranges, ok := added[f.Path]
+		if !ok {
+			continue
+		}
+		if f.Line == 0 || slices.ContainsFunc(ranges, func(r git.LineRange) bool {
+			return f.Line >= r.Start && f.Line <= r.End
+		}) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// sortFindings orders findings by position and drops duplicates, which
+// arise when a file is checked both in its package and its test variant.
+func sortFindings(findings []Finding) []Finding {
+	slices.SortFunc(findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(a.Path, b.Path),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Column, b.Column),
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return slices.Compact(findings)
+}
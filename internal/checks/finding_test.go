@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"reflect"
+	"testing"
+
+	"grua/internal/git"
+)
+
+func TestOnAddedLines(t *testing.T) {
+	findings := []Finding{
+		{Path: "a.go", Line: 3, Rule: "inside"},
+		{Path: "a.go", Line: 4, Rule: "after"},
+		{Path: "a.go", Line: 0, Rule: "file"},
+		{Path: "a.go", Line: 10, Rule: "second range"},
+		{Path: "b.go", Line: 1, Rule: "unchanged file"},
+		{Path: "b.go", Line: 1, Source: sourceCompile, Rule: "type"},
+	}
+	added := map[string][]git.LineRange{"a.go": {{Start: 1, End: 3}, {Start: 10, End: 10}}}
+	var got []string
+	for _, f := range OnAddedLines(findings, added) {
+		got = append(got, f.Rule)
+	}
+	if want := []string{"inside", "file", "second range", "type"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OnAddedLines = %q, want %q", got, want)
+	}
+}
+
+func TestSortFindings(t *testing.T) {
+	findings := []Finding{
+		{Path: "b.go", Line: 1, Rule: "x"},
+		{Path: "a.go", Line: 2, Column: 5, Rule: "x"},
+		{Path: "a.go", Line: 2, Column: 1, Rule: "y"},
+		{Path: "a.go", Line: 2, Column: 5, Rule: "x"},
+		{Path: "a.go", Line: 2, Column: 1, Rule: "x"},
+	}
+	want := []Finding{
+		{Path: "a.go", Line: 2, Column: 1, Rule: "x"},
+		{Path: "a.go", Line: 2, Column: 1, Rule: "y"},
+		{Path: "a.go", Line: 2, Column: 5, Rule: "x"},
+		{Path: "b.go", Line: 1, Rule: "x"},
+	}
+	if got := sortFindings(findings); !reflect.DeepEqual(got, want) {
+		t.Errorf("sortFindings =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package checks
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"grua/internal/gomod"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// load parses and type-checks the packages containing files, including
+// their test variants, with syntax for all dependencies so analyzers can
+// share facts across packages. Each module is loaded from its own root,
+// and files in no module are skipped. It never touches the network or
+// go.mod. A deleted file stands for the package left in its directory.
+func load(dir string, files []string) ([]*packages.Package, error) {
+	roots, err := gomod.Roots(dir, files)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs []*packages.Package
+	for _, root := range slices.Sorted(maps.Keys(roots)) {
+		cfg := &packages.Config{
+			Mode:  packages.LoadAllSyntax,
+			Dir:   root,
+			Tests: true,
+			Env:   append(os.Environ(), "GOPROXY=off", "GOFLAGS="),
+		}
+		var patterns []string
+		for _, file := range roots[root] {
+			path := filepath.Join(dir, file)
+			if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+				patterns = append(patterns, filepath.Dir(path))
+				continue
+			}
+			patterns = append(patterns, "file="+path)
+		}
+		loaded, err := packages.Load(cfg, patterns...)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, loaded...)
+	}
+	return pkgs, nil
+}
+
+// relPath returns filename relative to dir with forward slashes, or ""
+// if it is outside dir.
+func relPath(dir, filename string) string {
+	rel, err := filepath.Rel(dir, filename)
+	if err != nil || !filepath.IsLocal(rel) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
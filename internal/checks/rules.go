@@ -0,0 +1,145 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"grua/internal/git"
+)
+
+// RuleConfig tunes a rule, or every rule from a source.
+type RuleConfig struct {
+	// Enabled turns the rule off when false.
+	Enabled *bool `json:"enabled"`
+	// Severity, when set, replaces the rule's own.
+	Severity *Severity `json:"severity"`
+}
+
+// Rules configures rules by "source/rule", as in "errors/ignored-error",
+// or by source, as in "docs". A rule's own entry overrides its source's.
+type Rules map[string]RuleConfig
+
+// UnmarshalText parses "low", "medium" or "high".
+func (s *Severity) UnmarshalText(text []byte) error {
+	for _, sev := range []Severity{SeverityLow, SeverityMedium, SeverityHigh} {
+		if string(text) == sev.String() {
+			*s = sev
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+// Apply drops the findings of disabled rules and sets the configured
+// severities. Compile errors are left alone: code that does not build
+// cannot be configured away.
+func (r Rules) Apply(findings []Finding) []Finding {
+	if len(r) == 0 {
+		return findings
+	}
+	var kept []Finding
+	for _, f := range findings {
+		if !f.IsCompileError() {
+			enabled, severity := true, f.Severity
+			for _, name := range []string{f.Source, f.Source + "/" + f.Rule} {
+				cfg, ok := r[name]
+				if !ok {
+					continue
+				}
+				if cfg.Enabled != nil {
+					enabled = *cfg.Enabled
+				}
+				if cfg.Severity != nil {
+					severity = *cfg.Severity
+				}
+			}
+			if !enabled {
+				continue
+			}
+			f.Severity = severity
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// ignoreComment matches a suppression comment in any language's comment
+// syntax: grua:ignore, the rules it waives separated by commas, and the
+// reason.
+var ignoreComment = regexp.MustCompile(`grua:ignore\s+(\S+)\s*(.*)`)
+
+// suppression is an ignore comment on an added line.
+type suppression struct {
+	rules  []string
+	reason string
+	// alone is set when the comment has a line to itself, so it waives
+	// findings on the next line instead.
+	alone bool
+}
+
+// waives reports whether s names f's rule, as "source/rule", as the rule
+// alone or as its whole source.
+func (s suppression) waives(f Finding) bool {
+	return slices.Contains(s.rules, f.Source+"/"+f.Rule) || slices.Contains(s.rules, f.Rule) ||
+		slices.Contains(s.rules, f.Source)
+}
+
+// Suppress marks the findings waived by ignore comments on added lines,
+// either on the finding's line or alone on the line above. Suppressed
+// findings are kept, for review, but flagged. Compile errors and findings
+// about whole files cannot be suppressed.
+func Suppress(dir string, findings []Finding, added map[string][]git.LineRange) ([]Finding, error) {
+	comments := make(map[string]map[int]suppression)
+	for i, f := range findings {
+		if f.IsCompileError() || f.Line == 0 {
+			continue
+		}
+		byLine, ok := comments[f.Path]
+		if !ok {
+			var err error
+			if byLine, err = ignoreComments(dir, f.Path, added[f.Path]); err != nil {
+				return nil, err
+			}
+			comments[f.Path] = byLine
+		}
+		if s, ok := byLine[f.Line]; ok && !s.alone && s.waives(f) {
+			findings[i].Suppressed, findings[i].Reason = true, s.reason
+		} else if s, ok := byLine[f.Line-1]; ok && s.alone && s.waives(f) {
+			findings[i].Suppressed, findings[i].Reason = true, s.reason
+		}
+	}
+	return findings, nil
+}
+
+// ignoreComments returns the ignore comments on the added lines of a file
+// by line. A deleted file has no added lines, so no comments.
+func ignoreComments(dir, path string, added []git.LineRange) (map[int]suppression, error) {
+	byLine := make(map[int]suppression)
+	src, err := os.ReadFile(filepath.Join(dir, path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return byLine, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, line := range strings.Split(string(src), "\n") {
+		m := ignoreComment.FindStringSubmatchIndex(line)
+		if m == nil || !slices.ContainsFunc(added, func(r git.LineRange) bool {
+			return i+1 >= r.Start && i+1 <= r.End
+		}) {
+			continue
+		}
+		byLine[i+1] = suppression{
+			rules:  strings.Split(line[m[2]:m[3]], ","),
+			reason: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line[m[4]:m[5]]), "*/")),
+			alone:  strings.Trim(line[:m[0]], " \t/#*;-") == "",
+		}
+	}
+	return byLine, nil
+}
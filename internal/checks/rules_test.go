@@ -0,0 +1,135 @@
+package checks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"grua/internal/git"
+)
+
+func TestRulesApply(t *testing.T) {
+	var rules Rules
+	config := `{
+		"docs": {"enabled": false},
+		"errors": {"severity": "high"},
+		"errors/log-fatal": {"severity": "low"},
+		"vet": {"enabled": false},
+		"vet/printf": {"enabled": true}
+	}`
+	if err := json.Unmarshal([]byte(config), &rules); err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []Finding{
+		{Path: "a.go", Line: 1, Source: sourceDocs, Rule: "missing-doc", Severity: SeverityLow},
+		{Path: "a.go", Line: 2, Source: "errors", Rule: "ignored-error", Severity: SeverityMedium},
+		{Path: "a.go", Line: 3, Source: "errors", Rule: "log-fatal", Severity: SeverityMedium},
+		{Path: "a.go", Line: 4, Source: "vet", Rule: "shadow", Severity: SeverityMedium},
+		{Path: "a.go", Line: 5, Source: "vet", Rule: "printf", Severity: SeverityMedium},
+		{Path: "a.go", Line: 6, Source: "tests", Rule: "added-skip", Severity: SeverityHigh},
+	}
+	var got []string
+	for _, f := range rules.Apply(findings) {
+		got = append(got, f.Source+"/"+f.Rule+" "+f.Severity.String())
+	}
+	want := []string{"errors/ignored-error high", "errors/log-fatal low", "vet/printf medium", "tests/added-skip high"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply = %q, want %q", got, want)
+	}
+}
+
+func TestRulesApplyKeepsCompileErrors(t *testing.T) {
+	compile := Finding{Path: "a.go", Line: 1, Source: sourceCompile, Rule: "compile-error", Severity: SeverityHigh}
+	disabled := false
+	low := SeverityLow
+	rules := Rules{sourceCompile: {Enabled: &disabled, Severity: &low}}
+	if got := rules.Apply([]Finding{compile}); !reflect.DeepEqual(got, []Finding{compile}) {
+		t.Errorf("Apply = %+v, want the compile error unchanged", got)
+	}
+}
+
+func TestSeverityUnmarshalText(t *testing.T) {
+	var rules Rules
+	if err := json.Unmarshal([]byte(`{"docs": {"severity": "urgent"}}`), &rules); err == nil {
+		t.Error("unknown severity: got no error")
+	}
+}
+
+func TestIgnoreComments(t *testing.T) {
+	dir := t.TempDir()
+	src := `package p
+
+func f() {
+	os.Remove(tmp) //grua:ignore ignored-error the file may be gone
+	//grua:ignore errors/dropped-error,shadow checked by the caller
+	if err != nil {
+	}
+	x := 1 // grua:ignore shadow
+	/* grua:ignore vet reason in a block comment */
+	y := 2 //grua:ignore concurrency not on an added line
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	added := []git.LineRange{{Start: 4, End: 9}}
+
+	got, err := ignoreComments(dir, "p.go", added)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]suppression{
+		4: {rules: []string{"ignored-error"}, reason: "the file may be gone"},
+		5: {rules: []string{"errors/dropped-error", "shadow"}, reason: "checked by the caller", alone: true},
+		8: {rules: []string{"shadow"}, reason: ""},
+		9: {rules: []string{"vet"}, reason: "reason in a block comment", alone: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ignoreComments =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got, err := ignoreComments(dir, "deleted.go", added); err != nil || len(got) != 0 {
+		t.Errorf("deleted file: got %v, %v, want no comments", got, err)
+	}
+	if _, err := ignoreComments(dir, ".", added); err == nil {
+		t.Error("unreadable file: got no error")
+	}
+}
+
+func TestSuppress(t *testing.T) {
+	dir := t.TempDir()
+	src := `package p
+
+func f() {
+	os.Remove(tmp) //grua:ignore ignored-error the file may be gone
+	//grua:ignore errors/dropped-error checked by the caller
+	if err != nil {
+	}
+	_ = g() //grua:ignore shadow wrong rule
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	added := map[string][]git.LineRange{"p.go": {{Start: 4, End: 8}}}
+	findings := []Finding{
+		{Path: "p.go", Line: 4, Source: "errors", Rule: "ignored-error"},
+		{Path: "p.go", Line: 5, Source: "errors", Rule: "dropped-error"},
+		{Path: "p.go", Line: 6, Source: "errors", Rule: "dropped-error"},
+		{Path: "p.go", Line: 8, Source: "errors", Rule: "ignored-error"},
+		{Path: "p.go", Line: 4, Source: sourceCompile, Rule: "compile-error"},
+		{Path: "p.go", Line: 0, Source: "tests", Rule: "removed-test"},
+	}
+	got, err := Suppress(dir, findings, added)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"the file may be gone", "", "checked by the caller", "", "", ""} {
+		if got[i].Suppressed != (want != "") || got[i].Reason != want {
+			t.Errorf("finding %d: Suppressed = %v, Reason = %q, want reason %q", i, got[i].Suppressed, got[i].Reason, want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package checks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// sourceSecrets is the Source of findings about credentials in the change.
+const sourceSecrets = "secrets"
+
+// AllowlistFile names the file, at the repository root, listing secrets
+// the scan should accept: one regular expression per line matched against
+// the flagged text, or "path:" and a glob of files not to scan at all.
+// Blank lines and lines starting with # are ignored.
+const AllowlistFile = ".gruasecrets"
+
+// secretPattern is a kind of credential recognised by its shape.
+type secretPattern struct {
+	rule string
+	what string
+	re   *regexp.Regexp
+	// group is the submatch holding the secret, or 0 for the whole match.
+	group int
+}
+
+var secretPatterns = []secretPattern{
+	{"private-key", "private key", regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`), 0},
+	{"aws-key", "AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), 0},
+	{"aws-key", "AWS secret access key", regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})\b`), 1},
+	{"gcp-key", "Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`), 0},
+	{"gcp-key", "Google service account key", regexp.MustCompile(`"private_key_id"\s*:\s*"([0-9a-f]{40})"`), 1},
+	{"github-token", "GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`), 0},
+	{"github-token", "GitHub token", regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{82}\b`), 0},
+}
+
+// credentialName matches the names of credential-like variables.
+const credentialName = `([A-Za-z0-9_]*(?i:secret|password|passwd|token|api_?key|private_?key|access_?key|credentials?)[A-Za-z0-9_]*)`
+
+// envAssignment matches .env-style assignments of credential-like
+// variables, such as DB_PASSWORD=hunter22, in .env, shell and config files.
+var envAssignment = regexp.MustCompile(`^\s*(?:export\s+)?` + credentialName + `\s*=\s*["']?([^\s"'#]+)`)
+
+// goAssignment matches credential-like variables and fields set to a
+// string literal in Go source. Values computed at run time, as in
+// token = getToken(), are not credentials in the change.
+var goAssignment = regexp.MustCompile(`\b` + credentialName + "\\s*(?::=|=|:)\\s*[\"`]([^\\s\"`\\\\]+)[\"`]")
+
+// envExts are the extensions of shell and config files, whose assignments
+// are checked like those in .env files.
+var envExts = []string{".sh", ".bash", ".zsh", ".env", ".ini", ".cfg", ".conf", ".properties", ".toml"}
+
+// assignmentPattern returns the pattern for credential assignments in a
+// file, or nil for files whose assignments are not checked.
+func assignmentPattern(p string) *regexp.Regexp {
+	base := path.Base(p)
+	switch {
+	case path.Ext(base) == ".go":
+		return goAssignment
+	case strings.HasPrefix(base, ".env") || slices.Contains(envExts, path.Ext(base)):
+		return envAssignment
+	}
+	return nil
+}
+
+// placeholder matches assignment values that refer to a secret rather
+// than hold one.
+var placeholder = regexp.MustCompile(`^(\$|<|\{\{|%|\.\.\.|(?i:x+|changeme|change_me|example|dummy|test|none|null|nil|true|false|your[_-]))`)
+
+// entropyCandidate matches tokens long enough to be random keys.
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_\-]{24,}={0,2}`)
+
+// minEntropy is the Shannon entropy, in bits per character, above which a
+// mixed-case alphanumeric token is taken to be random.
+const minEntropy = 4.2
+
+// Allowlist holds the secrets and files a repository accepts.
+type Allowlist struct {
+	patterns []*regexp.Regexp
+	paths    []string
+}
+
+// LoadAllowlist reads the allowlist of the repository at dir. A missing
+// file allows nothing.
+func LoadAllowlist(dir string) (*Allowlist, error) {
+	allow := &Allowlist{}
+	src, err := os.ReadFile(filepath.Join(dir, AllowlistFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return allow, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "path:"):
+			glob := strings.TrimSpace(strings.TrimPrefix(line, "path:"))
+			if _, err := path.Match(glob, ""); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", AllowlistFile, i+1, err)
+			}
+			allow.paths = append(allow.paths, glob)
+		default:
+			re, err := regexp.Compile(line)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", AllowlistFile, i+1, err)
+			}
+			allow.patterns = append(allow.patterns, re)
+		}
+	}
+	return allow, nil
+}
+
+// skipsPath reports whether a file is not scanned: the allowlist itself,
+// go.sum, whose hashes look random, and files the allowlist names.
+func (a *Allowlist) skipsPath(p string) bool {
+	if p == AllowlistFile || path.Base(p) == "go.sum" {
+		return true
+	}
+	for _, glob := range a.paths {
+		if matchGlob(glob, p) || matchGlob(glob, path.Base(p)) {
+			return true
+		}
+		if strings.HasPrefix(p, strings.TrimSuffix(glob, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func matchGlob(glob, name string) bool {
+	ok, err := path.Match(glob, name)
+	return err == nil && ok
+}
+
+func (a *Allowlist) allows(secret string) bool {
+	for _, re := range a.patterns {
+		if re.MatchString(secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSecret reports whether a finding is about a credential in the change.
+func (f Finding) IsSecret() bool {
+	return f.Source == sourceSecrets
+}
+
+// ScanSecrets reports likely credentials in a file of any type: private
+// keys, cloud and GitHub tokens, credentials assigned in .env, shell and
+// config files or as string literals in Go, and other high-entropy
+// strings. Binary files are not scanned.
+func ScanSecrets(p string, src []byte, allow *Allowlist) []Finding {
+	if allow.skipsPath(p) || bytes.IndexByte(src[:min(len(src), 8000)], 0) >= 0 {
+		return nil
+	}
+
+	assignment := assignmentPattern(p)
+	var findings []Finding
+	for i, line := range strings.Split(string(src), "\n") {
+		// Earlier rules are more specific, so a string they match is not
+		// reported again as high-entropy.
+		var found []string
+		report := func(col int, rule, what, secret string) {
+			for _, f := range found {
+				if strings.Contains(f, secret) || strings.Contains(secret, f) {
+					return
+				}
+			}
+			found = append(found, secret)
+			if allow.allows(secret) {
+				return
+			}
+			msg := "possible " + what
+			if rule != "private-key" {
+				msg += " " + redact(secret)
+			}
+			findings = append(findings, Finding{
+				Path:     p,
+				Line:     i + 1,
+				Column:   col + 1,
+				Source:   sourceSecrets,
+				Rule:     rule,
+				Severity: SeverityHigh,
+				Message:  msg,
+			})
+		}
+
+		for _, pat := range secretPatterns {
+			for _, m := range pat.re.FindAllStringSubmatchIndex(line, -1) {
+				start, end := m[2*pat.group], m[2*pat.group+1]
+				report(start, pat.rule, pat.what, line[start:end])
+			}
+		}
+		if assignment != nil {
+			if m := assignment.FindStringSubmatchIndex(line); m != nil {
+				value := line[m[4]:m[5]]
+				if len(value) >= 6 && !placeholder.MatchString(value) {
+					report(m[4], "env-assignment", "credential in "+line[m[2]:m[3]], value)
+				}
+			}
+		}
+		for _, m := range entropyCandidate.FindAllStringIndex(line, -1) {
+			if token := line[m[0]:m[1]]; looksRandom(token) {
+				report(m[0], "high-entropy", "secret in high-entropy string", token)
+			}
+		}
+	}
+	return findings
+}
+
+// looksRandom reports whether a token mixes upper and lower case letters
+// and digits with high entropy, as keys do and identifiers do not.
+func looksRandom(token string) bool {
+	var upper, lower, digit bool
+	for _, r := range token {
+		upper = upper || unicode.IsUpper(r)
+		lower = lower || unicode.IsLower(r)
+		digit = digit || unicode.IsDigit(r)
+	}
+	return upper && lower && digit && entropy(token) >= minEntropy
+}
+
+// entropy is the Shannon entropy of s in bits per byte.
+func entropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	var h float64
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(len(s))
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
+
+// redact shows enough of a secret to find it without repeating it.
+func redact(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", min(len(secret)-4, 12))
+}
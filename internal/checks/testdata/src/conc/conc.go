@@ -0,0 +1,56 @@
+//go:build go1.21
+
+// Package conc is built as Go 1.21, where loop iterations share their
+// variables.
+package conc
+
+import (
+	"sync"
+	"time"
+)
+
+func loopCapture(items []int) {
+	for i, item := range items {
+		go func() {
+			_ = i    // want `goroutine captures loop variable i`
+			_ = item // want `goroutine captures loop variable item`
+		}()
+		go func(item int) {
+			_ = item
+		}(item)
+	}
+	for j := 0; j < 3; j++ {
+		go func() {
+			_ = j // want `goroutine captures loop variable j`
+		}()
+	}
+}
+
+func blockedSend() {
+	ch := make(chan int)
+	ch <- 1 // want `send on unbuffered channel ch blocks forever`
+
+	buffered := make(chan int, 1)
+	buffered <- 1
+
+	received := make(chan int)
+	go func() { received <- 1 }()
+	<-received
+
+	passed := make(chan int)
+	consume(passed)
+	passed <- 1
+}
+
+func consume(ch chan int) {}
+
+func sleepSync() {
+	time.Sleep(time.Millisecond)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+	}()
+	time.Sleep(time.Second) // want `time.Sleep used to wait for goroutines`
+	wg.Wait()
+}
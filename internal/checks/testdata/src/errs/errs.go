@@ -0,0 +1,54 @@
+package errs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"log"
+	"os"
+	"strings"
+)
+
+func ignored() {
+	os.Remove("x")       // want `error result of os.Remove is not checked`
+	_ = os.Remove("x")   // want `error result of os.Remove is discarded`
+	f, _ := os.Open("x") // want `error result of os.Open is discarded`
+	_ = f
+	fmt.Println("printing is fine")
+	fmt.Fprintf(os.Stderr, "so is this")
+}
+
+func writes(h hash.Hash, b *bytes.Buffer) {
+	h.Write(nil)
+	sha256.New().Write(nil)
+	b.Write(nil)
+	var buf bytes.Buffer
+	buf.WriteString("")
+	var sb strings.Builder
+	sb.WriteByte('a')
+	os.Stdout.Write(nil) // want `error result of \*os.File.Write is not checked`
+}
+
+func dropped() error {
+	err := errors.New("x")
+	if err != nil { // want `err is checked but neither returned, wrapped nor logged`
+		return errors.New("failed")
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func wrapping(err error) error {
+	if err != nil {
+		return fmt.Errorf("reading: %v", err) // want `fmt.Errorf formats an error without %w`
+	}
+	return fmt.Errorf("reading: %w", err)
+}
+
+func fatal() {
+	log.Fatal("bye") // want `log.Fatal in a library package exits the program`
+}
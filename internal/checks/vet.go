@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/passes/copylock"
+	"golang.org/x/tools/go/analysis/passes/loopclosure"
+	"golang.org/x/tools/go/analysis/passes/shadow"
+	"golang.org/x/tools/go/analysis/passes/waitgroup"
+	"golang.org/x/tools/go/analysis/suite/vet"
+	"golang.org/x/tools/go/packages"
+)
+
+// vetAnalyzers are the analyzers run by go vet, plus shadow and the error
+// handling and concurrency audits. Shadow is too noisy for go vet's
+// defaults but useful when only added lines are shown.
+var vetAnalyzers = append(append([]*analysis.Analyzer(nil), vet.Suite...), shadow.Analyzer, errorsAnalyzer, concurrencyAnalyzer)
+
+// runVet runs the vet analyzers over pkgs and returns their diagnostics in
+// the given files. Packages that do not type-check are skipped by the
+// analyzers; their errors are reported separately.
+func runVet(dir string, pkgs []*packages.Package, files map[string]bool) ([]Finding, error) {
+	graph, err := checker.Analyze(vetAnalyzers, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	seen := make(map[string]bool)
+	for act := range graph.All() {
+		if !act.IsRoot {
+			continue
+		}
+		for _, diag := range act.Diagnostics {
+			pos := act.Package.Fset.Position(diag.Pos)
+			path := relPath(dir, pos.Filename)
+			if !files[path] {
+				continue
+			}
+			source, rule, severity := "vet", act.Analyzer.Name, SeverityMedium
+			switch act.Analyzer {
+			case shadow.Analyzer:
+				severity = SeverityLow
+			case errorsAnalyzer:
+				source, rule = "errors", diag.Category
+				if rule == ruleDroppedError || rule == ruleUnwrappedError {
+					severity = SeverityLow
+				}
+			case concurrencyAnalyzer:
+				source, rule = sourceConcurrency, diag.Category
+				if rule == ruleSleepSync {
+					severity = SeverityLow
+				}
+			case copylock.Analyzer:
+				source, rule = sourceConcurrency, ruleCopiedLock
+			case waitgroup.Analyzer:
+				source, rule = sourceConcurrency, ruleWaitGroupAdd
+			case loopclosure.Analyzer:
+				source, rule = sourceConcurrency, ruleLoopCapture
+			}
+			// loopclosure and the concurrency audit both find captures by a
+			// loop's last statement.
+			key := fmt.Sprintf("%s:%d:%d:%s", path, pos.Line, pos.Column, rule)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, Finding{
+				Path:     path,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Source:   source,
+				Rule:     rule,
+				Severity: severity,
+				Message:  diag.Message,
+			})
+		}
+	}
+	return findings, nil
+}
@@ -0,0 +1,284 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// sourceTests is the Source of findings about weakened tests.
+const sourceTests = "tests"
+
+// testFunc is what a test function asserts, skips and tabulates.
+type testFunc struct {
+	assertions int
+	// skips are the skip calls by source text, for matching old to new.
+	skips map[string][]token.Pos
+	// tables are the element counts of test tables, by the name they are
+	// assigned to or, failing that, their order in the function.
+	tables   map[string]tableLit
+	position token.Pos
+}
+
+type tableLit struct {
+	cases int
+	pos   token.Pos
+}
+
+// IsWeakenedTest reports whether a finding is about a test the change
+// weakened.
+func (f Finding) IsWeakenedTest() bool {
+	return f.Source == sourceTests
+}
+
+// WeakenedTests compares the old and new versions of a test file and
+// reports what the change took out of it: removed test functions, added
+// skips, fewer assertions and smaller test tables. new is nil for a
+// deleted file. A version that does not parse is left to the compile
+// check.
+func WeakenedTests(path string, oldSrc, newSrc []byte) []Finding {
+	oldFset := token.NewFileSet()
+	oldFile, err := parser.ParseFile(oldFset, path, oldSrc, 0)
+	if err != nil {
+		return nil
+	}
+	oldFuncs := testFuncs(oldFset, oldFile, oldSrc, typeCheck(oldFset, oldFile))
+
+	var newFuncs map[string]*testFunc
+	newFset := token.NewFileSet()
+	if newSrc != nil {
+		newFile, err := parser.ParseFile(newFset, path, newSrc, 0)
+		if err != nil {
+			return nil
+		}
+		newFuncs = testFuncs(newFset, newFile, newSrc, typeCheck(newFset, newFile))
+	}
+
+	var findings []Finding
+	report := func(pos token.Pos, rule, format string, args ...any) {
+		line := 0
+		if pos.IsValid() {
+			line = newFset.Position(pos).Line
+		}
+		findings = append(findings, Finding{
+			Path:     path,
+			Line:     line,
+			Source:   sourceTests,
+			Rule:     rule,
+			Severity: SeverityHigh,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	for name := range oldFuncs {
+		if _, ok := newFuncs[name]; !ok {
+			report(token.NoPos, "removed-test", "%s was removed", name)
+		}
+	}
+	for name, fn := range newFuncs {
+		prev, ok := oldFuncs[name]
+		if !ok {
+			prev = &testFunc{}
+		}
+		for text, positions := range fn.skips {
+			for _, pos := range positions[min(len(prev.skips[text]), len(positions)):] {
+				report(pos, "added-skip", "%s is skipped with %s", name, text)
+			}
+		}
+		if fn.assertions < prev.assertions {
+			report(fn.position, "fewer-assertions", "%s has %d assertions, down from %d",
+				name, fn.assertions, prev.assertions)
+		}
+		for key, table := range fn.tables {
+			if was, ok := prev.tables[key]; ok && table.cases < was.cases {
+				report(table.pos, "fewer-cases", "test table in %s has %d cases, down from %d",
+					name, table.cases, was.cases)
+			}
+		}
+	}
+	return sortFindings(findings)
+}
+
+// testingPackage is the standard testing package, imported once.
+var testingPackage = sync.OnceValues(func() (*types.Package, error) {
+	return importer.Default().Import("testing")
+})
+
+// testingImporter imports only the testing package. Other imports, such as
+// assert and require, fail and are left as packages with no members, which
+// still name their import path.
+type testingImporter struct{}
+
+func (testingImporter) Import(path string) (*types.Package, error) {
+	if path == "testing" {
+		return testingPackage()
+	}
+	return nil, errors.New("not imported")
+}
+
+// typeCheck resolves the identifiers of one version of a test file on its
+// own, so calls can be told apart by their receiver's type. Anything from
+// other imports or other files of the package is unresolved, and the errors
+// that causes are ignored.
+func typeCheck(fset *token.FileSet, file *ast.File) *types.Info {
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	// With Error set, checking carries on past errors, and the one
+	// returned is among those expected.
+	conf := types.Config{Importer: testingImporter{}, Error: func(error) {}}
+	conf.Check(file.Name.Name, fset, []*ast.File{file}, info)
+	return info
+}
+
+// testFuncs indexes the test, benchmark, fuzz and example functions of a
+// file by name.
+func testFuncs(fset *token.FileSet, file *ast.File, src []byte, info *types.Info) map[string]*testFunc {
+	funcs := make(map[string]*testFunc)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Body == nil || !isTestName(fn.Name.Name) {
+			continue
+		}
+		tf := &testFunc{
+			skips:    make(map[string][]token.Pos),
+			tables:   make(map[string]tableLit),
+			position: fn.Pos(),
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				switch {
+				case isSkip(info, n):
+					text := nodeText(fset, src, n)
+					tf.skips[text] = append(tf.skips[text], n.Pos())
+				case isAssertion(info, n):
+					tf.assertions++
+				}
+			case *ast.AssignStmt:
+				if len(n.Lhs) == 1 && len(n.Rhs) == 1 {
+					if ident, ok := n.Lhs[0].(*ast.Ident); ok {
+						tf.addTable(ident.Name, n.Rhs[0])
+					}
+				}
+			case *ast.ValueSpec:
+				if len(n.Names) == 1 && len(n.Values) == 1 {
+					tf.addTable(n.Names[0].Name, n.Values[0])
+				}
+			case *ast.RangeStmt:
+				tf.addTable("", n.X)
+			}
+			return true
+		})
+		funcs[fn.Name.Name] = tf
+	}
+	return funcs
+}
+
+// addTable records expr if it is a test table: a slice or map literal
+// whose elements are themselves composite literals.
+func (tf *testFunc) addTable(name string, expr ast.Expr) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok || len(lit.Elts) == 0 {
+		return
+	}
+	switch lit.Type.(type) {
+	case *ast.ArrayType, *ast.MapType:
+	default:
+		return
+	}
+	elt := lit.Elts[0]
+	if kv, ok := elt.(*ast.KeyValueExpr); ok {
+		elt = kv.Value
+	}
+	if _, ok := elt.(*ast.CompositeLit); !ok {
+		return
+	}
+	if name == "" || name == "_" {
+		name = fmt.Sprintf("#%d", len(tf.tables))
+	}
+	tf.tables[name] = tableLit{cases: len(lit.Elts), pos: lit.Pos()}
+}
+
+func isTestName(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSkip reports whether call is Skip, Skipf or SkipNow on a testing.T,
+// B, F or TB.
+func isSkip(info *types.Info, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !isTestingValue(info.TypeOf(sel.X)) {
+		return false
+	}
+	switch sel.Sel.Name {
+	case "Skip", "Skipf", "SkipNow":
+		return true
+	}
+	return false
+}
+
+// isAssertion reports whether call reports a test failure: the failure
+// methods of testing.T and friends, or any function of the common assert
+// and require packages.
+func isAssertion(info *types.Info, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if ident, ok := sel.X.(*ast.Ident); ok {
+		if pkg, ok := info.Uses[ident].(*types.PkgName); ok {
+			base := path.Base(pkg.Imported().Path())
+			return base == "assert" || base == "require"
+		}
+	}
+	if !isTestingValue(info.TypeOf(sel.X)) {
+		return false
+	}
+	switch sel.Sel.Name {
+	case "Error", "Errorf", "Fatal", "Fatalf", "Fail", "FailNow":
+		return true
+	}
+	return false
+}
+
+// isTestingValue reports whether t is *testing.T, *testing.B, *testing.F
+// or testing.TB.
+func isTestingValue(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	want := []string{"TB"}
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t, want = ptr.Elem(), []string{"T", "B", "F"}
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "testing" && slices.Contains(want, obj.Name())
+}
+
+// nodeText returns the source text of n.
+func nodeText(fset *token.FileSet, src []byte, n ast.Node) string {
+	start, end := fset.Position(n.Pos()).Offset, fset.Position(n.End()).Offset
+	if start < 0 || end > len(src) || start > end {
+		return ""
+	}
+	return string(src[start:end])
+}
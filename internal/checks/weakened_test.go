@@ -0,0 +1,112 @@
+package checks
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWeakenedTests(t *testing.T) {
+	const header = `package p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+`
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{
+			name: "unchanged",
+			old:  "func TestA(t *testing.T) { t.Error(1) }\n",
+			new:  "func TestA(t *testing.T) { t.Error(1) }\n",
+		},
+		{
+			name: "removed test",
+			old:  "func TestA(t *testing.T) {}\nfunc TestB(t *testing.T) {}\n",
+			new:  "func TestA(t *testing.T) {}\n",
+			want: []string{"removed-test"},
+		},
+		{
+			name: "added skip",
+			old:  "func TestA(t *testing.T) {}\n",
+			new:  "func TestA(t *testing.T) { t.Skip(\"flaky\") }\n",
+			want: []string{"added-skip"},
+		},
+		{
+			name: "existing skip kept",
+			old:  "func TestA(t *testing.T) { t.Skip(\"slow\") }\n",
+			new:  "func TestA(t *testing.T) {\n\tt.Skip(\"slow\")\n}\n",
+		},
+		{
+			name: "skip on testing.TB",
+			old:  "func BenchmarkA(b *testing.B) { helper(b) }\nfunc helper(tb testing.TB) {}\n",
+			new:  "func BenchmarkA(b *testing.B) { var tb testing.TB = b; tb.SkipNow() }\n",
+			want: []string{"added-skip"},
+		},
+		{
+			name: "fewer testing.T failures",
+			old:  "func TestA(t *testing.T) { t.Errorf(\"a\"); t.Fatal(\"b\") }\n",
+			new:  "func TestA(t *testing.T) { t.Errorf(\"a\") }\n",
+			want: []string{"fewer-assertions"},
+		},
+		{
+			name: "fewer fuzz failures",
+			old:  "func FuzzA(f *testing.F) { f.Fail() }\n",
+			new:  "func FuzzA(f *testing.F) {}\n",
+			want: []string{"fewer-assertions"},
+		},
+		{
+			name: "fewer assert and require calls",
+			old:  "func TestA(t *testing.T) { assert.Equal(t, 1, 1); require.NoError(t, nil) }\n",
+			new:  "func TestA(t *testing.T) { assert.Equal(t, 1, 1) }\n",
+			want: []string{"fewer-assertions"},
+		},
+		{
+			name: "other Error and Skip methods",
+			old:  "type logger struct{}\nfunc (logger) Error(string) {}\nfunc (logger) Skip() {}\nfunc TestA(t *testing.T) { var l logger; l.Error(\"x\"); l.Error(\"y\") }\n",
+			new:  "type logger struct{}\nfunc (logger) Error(string) {}\nfunc (logger) Skip() {}\nfunc TestA(t *testing.T) { var l logger; l.Skip() }\n",
+		},
+		{
+			name: "errors package",
+			old:  "func TestA(t *testing.T) { var err error; _ = err.Error(); _ = err.Error() }\n",
+			new:  "func TestA(t *testing.T) {}\n",
+		},
+		{
+			name: "smaller table",
+			old:  "func TestA(t *testing.T) {\n\ttests := []struct{ in int }{{1}, {2}, {3}}\n\t_ = tests\n}\n",
+			new:  "func TestA(t *testing.T) {\n\ttests := []struct{ in int }{{1}, {2}}\n\t_ = tests\n}\n",
+			want: []string{"fewer-cases"},
+		},
+		{
+			name: "does not parse",
+			old:  "func TestA(t *testing.T) { t.Error(1) }\n",
+			new:  "func TestA(t *testing.T) {\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := WeakenedTests("p_test.go", []byte(header+tt.old), []byte(header+tt.new))
+			var rules []string
+			for _, f := range findings {
+				rules = append(rules, f.Rule)
+			}
+			if !slices.Equal(rules, tt.want) {
+				t.Errorf("rules = %q, want %q\n%+v", rules, tt.want, findings)
+			}
+		})
+	}
+}
+
+func TestWeakenedTestsDeletedFile(t *testing.T) {
+	old := []byte("package p\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {}\nfunc helper() {}\n")
+	findings := WeakenedTests("p_test.go", old, nil)
+	if len(findings) != 1 || findings[0].Rule != "removed-test" || findings[0].Line != 0 {
+		t.Errorf("findings = %+v, want TestA removed", findings)
+	}
+}
@@ -0,0 +1,247 @@
+// Package complexity measures the cyclomatic and cognitive complexity of
+// Go functions, and how a change moved them.
+package complexity
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+)
+
+// Func is the complexity of one function.
+type Func struct {
+	// Name is the function name, prefixed with the receiver type for
+	// methods, as in "Service.run".
+	Name string
+	// Line and EndLine are the lines the declaration spans.
+	Line    int
+	EndLine int
+	// Cyclomatic counts the independent paths through the function.
+	// Cognitive weighs control flow by how deeply it is nested, following
+	// the rules of cognitive complexity as described by SonarSource.
+	Cyclomatic int
+	Cognitive  int
+}
+
+// Funcs returns the complexity of each function declared in src, in
+// order.
+func Funcs(path string, src []byte) ([]Func, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var funcs []Func
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		funcs = append(funcs, Func{
+			Name:       funcName(fn),
+			Line:       fset.Position(fn.Pos()).Line,
+			EndLine:    fset.Position(fn.End()).Line,
+			Cyclomatic: cyclomatic(fn.Body),
+			Cognitive:  cognitive(fn.Body),
+		})
+	}
+	return funcs, nil
+}
+
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// cyclomatic is one plus the number of decision points: conditions,
+// loops, non-default cases and short-circuit operators.
+func cyclomatic(body *ast.BlockStmt) int {
+	n := 1
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			n++
+		case *ast.CaseClause:
+			if node.List != nil {
+				n++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				n++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				n++
+			}
+		}
+		return true
+	})
+	return n
+}
+
+// cognitive scores control flow structures one each plus their nesting
+// depth, else branches, labelled jumps and each run of like boolean
+// operators one each. Function literals add to the nesting of their
+// contents.
+func cognitive(body *ast.BlockStmt) int {
+	c := &cognitiveCounter{}
+	c.block(body, 0)
+	return c.score
+}
+
+type cognitiveCounter struct {
+	score int
+}
+
+func (c *cognitiveCounter) block(block *ast.BlockStmt, nesting int) {
+	if block == nil {
+		return
+	}
+	for _, stmt := range block.List {
+		c.stmt(stmt, nesting)
+	}
+}
+
+func (c *cognitiveCounter) stmt(stmt ast.Stmt, nesting int) {
+	switch s := stmt.(type) {
+	case *ast.IfStmt:
+		c.score += 1 + nesting
+		c.ifStmt(s, nesting)
+	case *ast.ForStmt:
+		c.score += 1 + nesting
+		c.node(s.Init, nesting)
+		c.node(s.Cond, nesting)
+		c.node(s.Post, nesting)
+		c.block(s.Body, nesting+1)
+	case *ast.RangeStmt:
+		c.score += 1 + nesting
+		c.node(s.X, nesting)
+		c.block(s.Body, nesting+1)
+	case *ast.SwitchStmt:
+		c.score += 1 + nesting
+		c.node(s.Init, nesting)
+		c.node(s.Tag, nesting)
+		c.clauses(s.Body, nesting+1)
+	case *ast.TypeSwitchStmt:
+		c.score += 1 + nesting
+		c.node(s.Init, nesting)
+		c.node(s.Assign, nesting)
+		c.clauses(s.Body, nesting+1)
+	case *ast.SelectStmt:
+		c.score += 1 + nesting
+		c.clauses(s.Body, nesting+1)
+	case *ast.BranchStmt:
+		if s.Label != nil || s.Tok == token.GOTO {
+			c.score++
+		}
+	case *ast.LabeledStmt:
+		c.stmt(s.Stmt, nesting)
+	case *ast.BlockStmt:
+		c.block(s, nesting)
+	default:
+		c.node(stmt, nesting)
+	}
+}
+
+// ifStmt scores the branches of an if statement, whose own increment has
+// been counted. An else if continues the chain without nesting further.
+func (c *cognitiveCounter) ifStmt(s *ast.IfStmt, nesting int) {
+	c.node(s.Init, nesting)
+	c.node(s.Cond, nesting)
+	c.block(s.Body, nesting+1)
+	switch e := s.Else.(type) {
+	case *ast.IfStmt:
+		c.score++
+		c.ifStmt(e, nesting)
+	case *ast.BlockStmt:
+		c.score++
+		c.block(e, nesting+1)
+	}
+}
+
+func (c *cognitiveCounter) clauses(body *ast.BlockStmt, nesting int) {
+	for _, clause := range body.List {
+		switch cl := clause.(type) {
+		case *ast.CaseClause:
+			for _, expr := range cl.List {
+				c.node(expr, nesting)
+			}
+			for _, stmt := range cl.Body {
+				c.stmt(stmt, nesting)
+			}
+		case *ast.CommClause:
+			c.node(cl.Comm, nesting)
+			for _, stmt := range cl.Body {
+				c.stmt(stmt, nesting)
+			}
+		}
+	}
+}
+
+// node scores what an expression or simple statement contains: boolean
+// operator runs and function literals.
+func (c *cognitiveCounter) node(node ast.Node, nesting int) {
+	if node == nil {
+		return
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			c.block(n.Body, nesting+1)
+			return false
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c.score += operatorRuns(n)
+				c.operands(n, nesting)
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// operands scores the operands of a boolean expression, whose operator
+// runs have been counted.
+func (c *cognitiveCounter) operands(expr ast.Expr, nesting int) {
+	if b, ok := ast.Unparen(expr).(*ast.BinaryExpr); ok && (b.Op == token.LAND || b.Op == token.LOR) {
+		c.operands(b.X, nesting)
+		c.operands(b.Y, nesting)
+		return
+	}
+	c.node(expr, nesting)
+}
+
+// operatorRuns counts the runs of like operators in a boolean expression
+// read left to right: a && b && c is one, a && b || c two.
+func operatorRuns(expr ast.Expr) int {
+	var ops []token.Token
+	var walk func(ast.Expr)
+	walk = func(e ast.Expr) {
+		b, ok := ast.Unparen(e).(*ast.BinaryExpr)
+		if !ok || (b.Op != token.LAND && b.Op != token.LOR) {
+			return
+		}
+		walk(b.X)
+		ops = append(ops, b.Op)
+		walk(b.Y)
+	}
+	walk(expr)
+	return len(slices.Compact(ops))
+}
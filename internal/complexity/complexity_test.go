@@ -0,0 +1,86 @@
+package complexity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		name                  string
+		body                  string
+		cyclomatic, cognitive int
+	}{
+		{"empty", ``, 1, 0},
+		{"if", `if a {}`, 2, 1},
+		{"mixed operators", `if a && b || c {}`, 4, 3},
+		{"like operators", `x := a && b && c; _ = x`, 3, 1},
+		{"parenthesised run", `x := a && (b || c); _ = x`, 3, 2},
+		{"else chain", `for { if a {} else if b {} else {} }`, 4, 5},
+		{"switch", `switch x { case 1: case 2, 3: default: }`, 3, 1},
+		{"nested switch", `for range xs { switch { case a: } }`, 3, 3},
+		{"select", `select { case <-ch: default: }`, 2, 1},
+		{"type switch", `switch v := x.(type) { case int: if v > 0 {} }`, 3, 3},
+		{"function literal", `f := func() { if a {} }; _ = f`, 2, 2},
+		{"labelled break", `outer: for { for { break outer } }`, 3, 4},
+		{"plain break", `for { break }`, 2, 1},
+		{"goto", `goto end; end:`, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package p\n\nfunc f() {\n" + tt.body + "\n}\n"
+			funcs, err := Funcs("p.go", []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(funcs) != 1 {
+				t.Fatalf("Funcs = %+v, want one", funcs)
+			}
+			if got := funcs[0]; got.Cyclomatic != tt.cyclomatic || got.Cognitive != tt.cognitive {
+				t.Errorf("cyclomatic %d, cognitive %d, want %d, %d", got.Cyclomatic, got.Cognitive, tt.cyclomatic, tt.cognitive)
+			}
+		})
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	src := `package p
+
+type T struct{}
+
+func (T) A() {}
+
+func (t *T) B() {
+}
+
+type G[K comparable, V any] struct{}
+
+func (g *G[K, V]) C() {}
+
+type H[K any] struct{}
+
+func (H[K]) D() {}
+
+func asm()
+
+func E() {}
+`
+	funcs, err := Funcs("p.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Func{
+		{Name: "T.A", Line: 5, EndLine: 5, Cyclomatic: 1},
+		{Name: "T.B", Line: 7, EndLine: 8, Cyclomatic: 1},
+		{Name: "G.C", Line: 12, EndLine: 12, Cyclomatic: 1},
+		{Name: "H.D", Line: 16, EndLine: 16, Cyclomatic: 1},
+		{Name: "E", Line: 20, EndLine: 20, Cyclomatic: 1},
+	}
+	if !reflect.DeepEqual(funcs, want) {
+		t.Errorf("Funcs =\n%+v\nwant\n%+v", funcs, want)
+	}
+
+	if _, err := Funcs("p.go", []byte("package p\n\nfunc {")); err == nil {
+		t.Error("syntax error: got no error")
+	}
+}
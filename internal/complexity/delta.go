@@ -0,0 +1,73 @@
+package complexity
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Delta compares the complexity of a changed function before and after a
+// change.
+type Delta struct {
+	Name string
+	// Line is where the function starts in the new version.
+	Line int
+	// Old is the zero Func if the function is new.
+	Old Func
+	New Func
+}
+
+// IsNew reports whether the function did not exist before the change.
+func (d Delta) IsNew() bool {
+	return d.Old.Name == ""
+}
+
+// CyclomaticDelta and CognitiveDelta are how much the change added to each
+// measure; negative if it simplified the function.
+func (d Delta) CyclomaticDelta() int { return d.New.Cyclomatic - d.Old.Cyclomatic }
+func (d Delta) CognitiveDelta() int  { return d.New.Cognitive - d.Old.Cognitive }
+
+// Compare matches functions by name, which for methods includes the
+// receiver type, and returns the deltas of those the change touched: new functions, functions containing a changed line, and
+// any whose complexity moved. changed reports whether a line of the new
+// version was added. Deltas are ordered by the growth of their cognitive
+// complexity, largest first.
+func Compare(oldFuncs, newFuncs []Func, changed func(line int) bool) []Delta {
+	old := make(map[string]Func, len(oldFuncs))
+	for i, key := range funcKeys(oldFuncs) {
+		old[key] = oldFuncs[i]
+	}
+
+	var deltas []Delta
+	for i, key := range funcKeys(newFuncs) {
+		fn := newFuncs[i]
+		d := Delta{Name: fn.Name, Line: fn.Line, Old: old[key], New: fn}
+		touched := d.IsNew() || d.CyclomaticDelta() != 0 || d.CognitiveDelta() != 0
+		for line := fn.Line; !touched && line <= fn.EndLine; line++ {
+			touched = changed(line)
+		}
+		if touched {
+			deltas = append(deltas, d)
+		}
+	}
+	slices.SortStableFunc(deltas, func(a, b Delta) int {
+		return cmp.Compare(b.CognitiveDelta(), a.CognitiveDelta())
+	})
+	return deltas
+}
+
+// funcKeys returns the name of each function, numbered from the second
+// declaration on for names that can be declared more than once in a
+// package, init and _, so that they are matched in order.
+func funcKeys(funcs []Func) []string {
+	keys := make([]string, len(funcs))
+	seen := make(map[string]int)
+	for i, fn := range funcs {
+		keys[i] = fn.Name
+		if n := seen[fn.Name]; n > 0 {
+			keys[i] = fmt.Sprintf("%s#%d", fn.Name, n+1)
+		}
+		seen[fn.Name]++
+	}
+	return keys
+}
@@ -0,0 +1,82 @@
+package complexity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	oldFuncs := []Func{
+		{Name: "Same", Line: 1, EndLine: 3, Cyclomatic: 2, Cognitive: 1},
+		{Name: "Edited", Line: 5, EndLine: 9, Cyclomatic: 3, Cognitive: 2},
+		{Name: "Grown", Line: 11, EndLine: 15, Cyclomatic: 2, Cognitive: 1},
+		{Name: "Simpler", Line: 17, EndLine: 25, Cyclomatic: 5, Cognitive: 6},
+		{Name: "Removed", Line: 27, EndLine: 30, Cyclomatic: 1},
+	}
+	newFuncs := []Func{
+		{Name: "Same", Line: 1, EndLine: 3, Cyclomatic: 2, Cognitive: 1},
+		{Name: "Edited", Line: 5, EndLine: 9, Cyclomatic: 3, Cognitive: 2},
+		{Name: "Grown", Line: 11, EndLine: 20, Cyclomatic: 4, Cognitive: 5},
+		{Name: "Simpler", Line: 22, EndLine: 26, Cyclomatic: 2, Cognitive: 1},
+		{Name: "Added", Line: 28, EndLine: 30, Cyclomatic: 2, Cognitive: 1},
+	}
+	changed := func(line int) bool { return line == 7 }
+
+	var got []string
+	for _, d := range Compare(oldFuncs, newFuncs, changed) {
+		got = append(got, d.Name)
+	}
+	// Same is untouched, and Removed is not in the new version.
+	want := []string{"Grown", "Added", "Edited", "Simpler"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare = %q, want %q", got, want)
+	}
+
+	deltas := Compare(oldFuncs, newFuncs, changed)
+	if d := deltas[0]; d.IsNew() || d.CyclomaticDelta() != 2 || d.CognitiveDelta() != 4 || d.Line != 11 {
+		t.Errorf("Grown = %+v", d)
+	}
+	if d := deltas[1]; !d.IsNew() || d.CyclomaticDelta() != 2 || d.CognitiveDelta() != 1 {
+		t.Errorf("Added = %+v", d)
+	}
+	if d := deltas[3]; d.CyclomaticDelta() != -3 || d.CognitiveDelta() != -5 || d.Line != 22 {
+		t.Errorf("Simpler = %+v", d)
+	}
+}
+
+func TestCompareRepeatedNames(t *testing.T) {
+	src := `package p
+
+type A struct{}
+type B struct{ ok bool }
+
+func (A) String() string { return "a" }
+
+func (b *B) String() string {
+	if b.ok {
+		return "b"
+	}
+	return ""
+}
+
+func init() {}
+
+func init() {
+	for range 3 {
+	}
+}
+`
+	funcs, err := Funcs("p.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unchanged, each function and each init meets its own old version.
+	if deltas := Compare(funcs, funcs, func(int) bool { return false }); len(deltas) != 0 {
+		t.Errorf("Compare = %+v, want no deltas", deltas)
+	}
+
+	// Removing the last init leaves the first matched to its old version.
+	if deltas := Compare(funcs, funcs[:3], func(int) bool { return false }); len(deltas) != 0 {
+		t.Errorf("without the second init: Compare = %+v, want no deltas", deltas)
+	}
+}
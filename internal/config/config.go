@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"grua/internal/checks"
+)
+
+// Config holds user settings loaded from the config file.
+type Config struct {
+	// ContextLines is the number of unchanged lines shown around each
+	// change, as passed to git diff -U.
+	ContextLines int `json:"context_lines"`
+	// Editor overrides $VISUAL and $EDITOR.
+	Editor string `json:"editor"`
+	// EditorTemplates maps an editor's program name to the arguments that
+	// open a file at a line. {file} and {line} are substituted. Entries
+	// are merged over the built-in templates.
+	EditorTemplates map[string]string `json:"editor_templates"`
+	// Rules enables, disables and sets the severity of check rules.
+	Rules checks.Rules `json:"rules"`
+}
+
+// Default returns the settings used when no config file exists.
+func Default() Config {
+	return Config{
+		ContextLines: 3,
+		EditorTemplates: map[string]string{
+			"vi":          "+{line} {file}",
+			"vim":         "+{line} {file}",
+			"nvim":        "+{line} {file}",
+			"nano":        "+{line} {file}",
+			"emacs":       "+{line} {file}",
+			"emacsclient": "+{line} {file}",
+			"micro":       "+{line} {file}",
+			"kak":         "+{line} {file}",
+			"hx":          "{file}:{line}",
+			"helix":       "{file}:{line}",
+			"subl":        "{file}:{line}",
+			"zed":         "{file}:{line}",
+			"code":        "--goto {file}:{line}",
+			"codium":      "--goto {file}:{line}",
+			"cursor":      "--goto {file}:{line}",
+		},
+	}
+}
+
+// Path returns the location of the config file.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "grua", "config.json"), nil
+}
+
+// Load reads the config file, falling back to defaults for anything it does
+// not set. A missing file is not an error.
+func Load() (Config, error) {
+	cfg := Default()
+
+	path, err := Path()
+	if err != nil {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	templates := cfg.EditorTemplates
+	cfg.EditorTemplates = nil
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	for name, template := range cfg.EditorTemplates {
+		templates[name] = template
+	}
+	cfg.EditorTemplates = templates
+	if cfg.ContextLines < 0 {
+		cfg.ContextLines = 0
+	}
+	return cfg, nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeConfig points the user config directory at a temporary one and
+// writes content to the config file in it, unless content is empty.
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	if content == "" {
+		return
+	}
+	path := filepath.Join(dir, "grua", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	writeConfig(t, "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ContextLines != 3 {
+		t.Errorf("ContextLines = %d, want the default 3", cfg.ContextLines)
+	}
+}
+
+func TestLoadContextLines(t *testing.T) {
+	tests := []struct {
+		content string
+		want    int
+	}{
+		{`{"context_lines": 10}`, 10},
+		{`{"context_lines": 0}`, 0},
+		{`{"context_lines": -4}`, 0},
+		{`{"editor": "vim"}`, 3},
+	}
+	for _, tt := range tests {
+		writeConfig(t, tt.content)
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.content, err)
+		}
+		if cfg.ContextLines != tt.want {
+			t.Errorf("%s: ContextLines = %d, want %d", tt.content, cfg.ContextLines, tt.want)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	writeConfig(t, `{"context_lines": "many"}`)
+	cfg, err := Load()
+	if err == nil {
+		t.Fatal("got no error")
+	}
+	if cfg.ContextLines != 3 {
+		t.Errorf("ContextLines = %d, want the default after an error", cfg.ContextLines)
+	}
+}
+
+func TestLoadEditorTemplates(t *testing.T) {
+	writeConfig(t, `{"editor": "myedit --wait", "editor_templates": {"myedit": "-l {line} {file}", "vim": "{file} +{line}"}}`)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Editor != "myedit --wait" {
+		t.Errorf("Editor = %q", cfg.Editor)
+	}
+	for name, want := range map[string]string{
+		"myedit": "-l {line} {file}",
+		"vim":    "{file} +{line}",
+		"code":   "--goto {file}:{line}",
+	} {
+		if got := cfg.EditorTemplates[name]; got != want {
+			t.Errorf("template for %s = %q, want %q", name, got, want)
+		}
+	}
+	if Default().EditorTemplates["vim"] != "+{line} {file}" {
+		t.Error("Load changed the default templates")
+	}
+}
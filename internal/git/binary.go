@@ -0,0 +1,214 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LargeDiffLines is the default cap on diff lines loaded for one file.
+	LargeDiffLines = 5000
+	// largeFileBytes marks unversioned files as large, since they have no
+	// numstat to count lines from.
+	largeFileBytes = 1 << 20
+	// sniffLen matches the amount of content git inspects when deciding
+	// whether a file is binary.
+	sniffLen = 8000
+	// maxLineBytes bounds a single line so minified or generated files do
+	// not fail the scanner.
+	maxLineBytes = 4 << 20
+)
+
+// BlobSummary describes the two sides of a file that is not shown as a
+// text diff. A size of -1 means that side does not exist.
+type BlobSummary struct {
+	OldSize int64
+	NewSize int64
+	Type    string
+}
+
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	return scanner
+}
+
+// isBinary applies git's heuristic: content with a NUL byte is binary.
+func isBinary(head []byte) bool {
+	return bytes.IndexByte(head, 0) >= 0
+}
+
+type numstat struct {
+	added   int
+	removed int
+	binary  bool
+}
+
+// addSizeDetails marks binary and large files: tracked files from git diff
+// --numstat, unversioned files by reading their first bytes.
+func (s *Service) addSizeDetails(files []FileStatus) error {
+	staged, err := s.getNumstat("--staged")
+	if err != nil {
+		return err
+	}
+	unstaged, err := s.getNumstat()
+	if err != nil {
+		return err
+	}
+
+	for i := range files {
+		f := &files[i]
+		if f.Unversioned {
+			f.Binary, f.Large = s.sniffFile(f.Path)
+			continue
+		}
+		stats := unstaged
+		if f.Staged {
+			stats = staged
+		}
+		if st, ok := stats[f.Path]; ok {
+			f.Binary = st.binary
+			f.Large = st.added+st.removed > LargeDiffLines
+		}
+	}
+	return nil
+}
+
+// getNumstat runs git diff --numstat -z with the given revision arguments
+// and returns the counts keyed by new path. Binary files are reported by git
+// as "-" for both counts.
+func (s *Service) getNumstat(revs ...string) (map[string]numstat, error) {
+	args := append([]string{"diff", "--numstat", "-z", "-M", "-C"}, revs...)
+	output, err := s.run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]numstat)
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i < len(fields); i++ {
+		// added<TAB>removed<TAB>path, where path is empty for renames and
+		// copies and followed by the old and new paths as separate fields.
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		path := parts[2]
+		if path == "" && i+2 < len(fields) {
+			path = fields[i+2]
+			i += 2
+		}
+		st := numstat{binary: parts[0] == "-" && parts[1] == "-"}
+		st.added, _ = strconv.Atoi(parts[0])
+		st.removed, _ = strconv.Atoi(parts[1])
+		stats[path] = st
+	}
+	return stats, nil
+}
+
+// sniffFile reports whether a working tree file looks binary and whether
+// it is too large to diff comfortably.
+func (s *Service) sniffFile(path string) (binary, large bool) {
+	f, err := os.Open(filepath.Join(s.repoPath, path))
+	if err != nil {
+		return false, false
+	}
+	defer f.Close()
+
+	head := make([]byte, sniffLen)
+	n, _ := io.ReadFull(f, head)
+	if info, err := f.Stat(); err == nil {
+		large = info.Size() > largeFileBytes
+	}
+	return isBinary(head[:n]), large
+}
+
+// getBlobSummary collects the sizes and content type of both sides of a
+// file. Errors leave the affected side marked as missing.
+func (s *Service) getBlobSummary(file FileStatus) *BlobSummary {
+	summary := &BlobSummary{OldSize: -1, NewSize: -1}
+
+	oldPath := file.Path
+	if file.OldPath != "" {
+		oldPath = file.OldPath
+	}
+
+	oldRev := ":" + oldPath
+	switch {
+	case file.Commit != "":
+		parent, err := s.commitParent(file.Commit)
+		if err != nil {
+			return summary
+		}
+		oldRev = parent + ":" + oldPath
+	case file.Staged:
+		oldRev = "HEAD:" + oldPath
+	}
+	if !file.Unversioned {
+		summary.OldSize = s.blobSize(oldRev)
+	}
+
+	var head []byte
+	switch {
+	case file.Deleted:
+		head = s.blobHead(oldRev)
+	case file.Commit != "":
+		summary.NewSize = s.blobSize(file.Commit + ":" + file.Path)
+		head = s.blobHead(file.Commit + ":" + file.Path)
+	case file.Staged:
+		summary.NewSize = s.blobSize(":" + file.Path)
+		head = s.blobHead(":" + file.Path)
+	default:
+		if info, err := os.Stat(filepath.Join(s.repoPath, file.Path)); err == nil {
+			summary.NewSize = info.Size()
+			if f, err := os.Open(filepath.Join(s.repoPath, file.Path)); err == nil {
+				head = make([]byte, 512)
+				n, _ := io.ReadFull(f, head)
+				head = head[:n]
+				f.Close()
+			}
+		}
+	}
+
+	if len(head) > 0 {
+		summary.Type = http.DetectContentType(head)
+	}
+	return summary
+}
+
+func (s *Service) blobSize(rev string) int64 {
+	output, err := s.run("cat-file", "-s", rev)
+	if err != nil {
+		return -1
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// blobHead returns the first bytes of a blob without reading all of it.
+func (s *Service) blobHead(rev string) []byte {
+	cmd := exec.Command("git", "cat-file", "blob", rev)
+	cmd.Dir = s.repoPath
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		return nil
+	}
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(stdout, head)
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	return head[:n]
+}
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBinaryAndLargeFiles(t *testing.T) {
+	dir, gitCmd := testRepo(t)
+	writeFile(t, dir, "img.go", "\x89PNG\r\n\x1a\n\x00\x00")
+	writeFile(t, dir, "small.go", "package a\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "init")
+
+	writeFile(t, dir, "img.go", "\x89PNG\r\n\x1a\n\x00\x00\x00\x01")
+	writeFile(t, dir, "small.go", "package a\n\nvar x = 1\n")
+	writeFile(t, dir, "big.go", "package a\n"+strings.Repeat("var _ = 0\n", LargeDiffLines))
+	gitCmd("add", "big.go")
+	writeFile(t, dir, "blob.go", "\x00\x01\x02")
+
+	s := NewService(dir)
+	files, err := s.GetChangedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]FileStatus)
+	for _, f := range files {
+		got[f.Path] = f
+	}
+	for path, want := range map[string]struct{ binary, large bool }{
+		"img.go":   {binary: true},
+		"small.go": {},
+		"big.go":   {large: true},
+		"blob.go":  {binary: true},
+	} {
+		f, ok := got[path]
+		if !ok {
+			t.Errorf("%s is not listed", path)
+			continue
+		}
+		if f.Binary != want.binary || f.Large != want.large {
+			t.Errorf("%s: Binary = %v, Large = %v, want %v, %v", path, f.Binary, f.Large, want.binary, want.large)
+		}
+	}
+
+	diff, err := s.GetDiff(got["img.go"], DefaultDiffOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Binary || len(diff.Hunks) != 0 || diff.Summary == nil {
+		t.Fatalf("binary diff = %+v, want a summary and no hunks", diff)
+	}
+	if diff.Summary.OldSize != 10 || diff.Summary.NewSize != 12 || diff.Summary.Type != "image/png" {
+		t.Errorf("summary = %+v, want 10 → 12 bytes of image/png", diff.Summary)
+	}
+
+	unversioned, err := s.GetUnversionedDiff("blob.go", DefaultDiffOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !unversioned.Binary || unversioned.Summary == nil || unversioned.Summary.OldSize != -1 || unversioned.Summary.NewSize != 3 {
+		t.Errorf("unversioned binary diff = %+v, summary %+v", unversioned, unversioned.Summary)
+	}
+
+	staged, err := s.GetDiff(got["big.go"], DefaultDiffOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !staged.Truncated || staged.TotalLines != LargeDiffLines {
+		t.Errorf("large diff: Truncated = %v, TotalLines = %d", staged.Truncated, staged.TotalLines)
+	}
+}
+
+func TestGetUnversionedDiffCap(t *testing.T) {
+	dir, _ := testRepo(t)
+	writeFile(t, dir, "notes.txt", strings.Repeat("line\n", 10))
+	diff, err := NewService(dir).GetUnversionedDiff("notes.txt", DiffOptions{MaxLines: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Truncated || diff.TotalLines != 10 || len(diff.Hunks[0].Lines) != 4 {
+		t.Errorf("Truncated = %v, TotalLines = %d, lines = %d, want 4 of 10", diff.Truncated, diff.TotalLines, len(diff.Hunks[0].Lines))
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	for _, tt := range []struct {
+		head string
+		want bool
+	}{
+		{"package main\n", false},
+		{"", false},
+		{"héllo ✓", false},
+		{"GIF89a\x00\x01", true},
+	} {
+		if got := isBinary([]byte(tt.head)); got != tt.want {
+			t.Errorf("isBinary(%q) = %v, want %v", tt.head, got, tt.want)
+		}
+	}
+}
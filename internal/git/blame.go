@@ -0,0 +1,145 @@
+package git
+
+import (
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlameCommit describes the commit that last changed a line.
+type BlameCommit struct {
+	Hash    string
+	Author  string
+	Time    time.Time
+	Summary string
+}
+
+// Committed reports whether the line is in a commit, rather than staged
+// since HEAD.
+func (c *BlameCommit) Committed() bool {
+	return strings.Trim(c.Hash, "0") != ""
+}
+
+// Blame maps the lines of a file at a revision to the commits that last
+// changed them. Rev is ":" for the version in the index.
+type Blame struct {
+	Rev   string
+	Path  string
+	lines []*BlameCommit
+}
+
+// Line returns the commit for a 1-based line number, or nil if the line is
+// out of range.
+func (b *Blame) Line(n int) *BlameCommit {
+	if b == nil || n < 1 || n > len(b.lines) {
+		return nil
+	}
+	return b.lines[n-1]
+}
+
+// GetBlame blames the old side of a file's diff: the index for unstaged
+// changes, HEAD for staged ones, or the first parent for a file changed by
+// a commit. Line numbers match the diff's old line numbers. Files without
+// an old side, such as new files, have no blame.
+func (s *Service) GetBlame(file FileStatus) (*Blame, error) {
+	if file.Unversioned && file.Commit == "" {
+		return nil, nil
+	}
+
+	// Revision specs name the index by an empty revision, as in ":path".
+	rev := ""
+	if file.Commit != "" {
+		parent, err := s.commitParent(file.Commit)
+		if err != nil {
+			return nil, err
+		}
+		rev = parent
+	} else if file.Staged {
+		rev = "HEAD"
+	}
+	path := file.Path
+	if file.OldPath != "" {
+		path = file.OldPath
+	}
+	if _, err := s.run("cat-file", "-e", rev+":"+path); err != nil {
+		return nil, nil
+	}
+
+	var output []byte
+	var err error
+	if rev == "" {
+		output, err = s.blameIndex(path)
+		rev = ":"
+	} else {
+		output, err = s.run("blame", "--porcelain", rev, "--", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Blame{Rev: rev, Path: path, lines: parseBlame(string(output))}, nil
+}
+
+// blameIndex blames the staged version of a file. Lines staged since HEAD
+// are attributed to no commit.
+func (s *Service) blameIndex(path string) ([]byte, error) {
+	staged, err := s.run("show", ":"+path)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("git", "blame", "--porcelain", "--contents", "-", "--", path)
+	cmd.Dir = s.repoPath
+	cmd.Stdin = bytes.NewReader(staged)
+	return cmd.Output()
+}
+
+// parseBlame parses git blame --porcelain output. Each line starts with a
+// "<hash> <orig line> <final line> [<count>]" header; the first time a
+// commit appears, key-value lines describing it follow. The line content
+// comes last, prefixed with a tab.
+func parseBlame(output string) []*BlameCommit {
+	commits := make(map[string]*BlameCommit)
+	var lines []*BlameCommit
+	var current *BlameCommit
+	expectHeader := true
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			expectHeader = true
+			continue
+		}
+		if expectHeader {
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+			current = commits[fields[0]]
+			if current == nil {
+				current = &BlameCommit{Hash: fields[0]}
+				commits[fields[0]] = current
+			}
+			if n, err := strconv.Atoi(fields[2]); err == nil && n >= 1 {
+				for len(lines) < n {
+					lines = append(lines, nil)
+				}
+				lines[n-1] = current
+			}
+			expectHeader = false
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-time":
+			if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+				current.Time = time.Unix(secs, 0)
+			}
+		case "summary":
+			current.Summary = value
+		}
+	}
+	return lines
+}
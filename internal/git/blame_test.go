@@ -0,0 +1,112 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBlame(t *testing.T) {
+	a := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	b := "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	output := strings.Join([]string{
+		a + " 1 1 2",
+		"author Ann",
+		"author-mail <ann@example.com>",
+		"author-time 1700000000",
+		"author-tz +0000",
+		"summary First commit",
+		"filename main.go",
+		"\tpackage main",
+		a + " 2 2",
+		"\t",
+		b + " 5 3 1",
+		"author Bob Builder",
+		"author-time 1710000000",
+		"summary Add f: with a colon",
+		"previous " + a + " main.go",
+		"filename main.go",
+		"\tfunc f() {}",
+		a + " 3 4 1",
+		"\t// author Eve",
+		"",
+	}, "\n")
+
+	lines := parseBlame(output)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4", len(lines))
+	}
+	for i, want := range []string{a, a, b, a} {
+		if lines[i] == nil || lines[i].Hash != want {
+			t.Errorf("line %d = %+v, want commit %.7s", i+1, lines[i], want)
+		}
+	}
+	if lines[0] != lines[3] {
+		t.Error("lines of the same commit do not share it")
+	}
+	if c := lines[0]; c.Author != "Ann" || c.Summary != "First commit" || c.Time.Unix() != 1700000000 {
+		t.Errorf("first commit = %+v", c)
+	}
+	if c := lines[2]; c.Author != "Bob Builder" || c.Summary != "Add f: with a colon" || c.Time.Unix() != 1710000000 {
+		t.Errorf("second commit = %+v", c)
+	}
+}
+
+func TestGetBlame(t *testing.T) {
+	dir, gitCmd := testRepo(t)
+	writeFile(t, dir, "a.go", "package a\n\nvar x = 1\n")
+	gitCmd("add", "a.go")
+	gitCmd("commit", "-q", "-m", "add a")
+	head := strings.TrimSpace(gitCmd("rev-parse", "HEAD"))
+
+	// Stage an inserted line, then change the file again, so the old side
+	// of the unstaged diff is the index rather than HEAD.
+	writeFile(t, dir, "a.go", "package a\n\nvar w = 0\nvar x = 1\n")
+	gitCmd("add", "a.go")
+	writeFile(t, dir, "a.go", "package a\n\nvar w = 0\nvar x = 2\n")
+
+	s := NewService(dir)
+	unstaged, err := s.GetBlame(FileStatus{Path: "a.go", Status: "M"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unstaged.Rev != ":" {
+		t.Errorf("unstaged Rev = %q, want the index", unstaged.Rev)
+	}
+	if c := unstaged.Line(3); c == nil || c.Committed() {
+		t.Errorf("staged line 3 = %+v, want it not committed", c)
+	}
+	if c := unstaged.Line(4); c == nil || c.Hash != head {
+		t.Errorf("line 4 = %+v, want %.7s", c, head)
+	}
+
+	staged, err := s.GetBlame(FileStatus{Path: "a.go", Status: "M", Staged: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staged.Rev != "HEAD" {
+		t.Errorf("staged Rev = %q, want HEAD", staged.Rev)
+	}
+	if c := staged.Line(3); c == nil || c.Hash != head {
+		t.Errorf("staged diff line 3 = %+v, want %.7s", c, head)
+	}
+	if c := staged.Line(4); c != nil {
+		t.Errorf("staged diff line 4 = %+v, want none", c)
+	}
+
+	// A new file that is staged and changed again is blamed in the index,
+	// where all of it is uncommitted.
+	writeFile(t, dir, "b.go", "package a\n")
+	gitCmd("add", "b.go")
+	writeFile(t, dir, "b.go", "package a\n\nvar y = 1\n")
+	added, err := s.GetBlame(FileStatus{Path: "b.go", Status: "M"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := added.Line(1); c == nil || c.Committed() {
+		t.Errorf("new file line 1 = %+v, want it not committed", c)
+	}
+
+	if b, err := s.GetBlame(FileStatus{Path: "b.go", Status: "A", Staged: true}); b != nil || err != nil {
+		t.Errorf("new file against HEAD = %+v, %v, want no blame", b, err)
+	}
+}
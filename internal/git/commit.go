@@ -0,0 +1,54 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// GetStagedFiles returns every staged file, not only .go files, since a
+// commit includes all of them.
+func (s *Service) GetStagedFiles() ([]FileStatus, error) {
+	entries, err := s.getStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileStatus
+	for _, e := range entries {
+		for _, f := range e.files() {
+			if f.Staged {
+				files = append(files, f)
+			}
+		}
+	}
+	return files, nil
+}
+
+// HeadMessage returns the full message of the HEAD commit.
+func (s *Service) HeadMessage() (string, error) {
+	return s.CommitMessage("HEAD")
+}
+
+// CommitMessage returns the full message of a commit.
+func (s *Service) CommitMessage(rev string) (string, error) {
+	output, err := s.run("log", "-1", "--format=%B", rev)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// Commit commits the index with message, amending HEAD if amend is set. It
+// returns the combined output of git and any hooks, which is also useful
+// when the commit fails.
+func (s *Service) Commit(message string, amend bool) (string, error) {
+	args := []string{"commit", "-F", "-"}
+	if amend {
+		args = append(args, "--amend")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.repoPath
+	cmd.Stdin = strings.NewReader(message)
+	output, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(output)), err
+}
@@ -0,0 +1,57 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommit(t *testing.T) {
+	dir, gitCmd := testRepo(t)
+	gitCmd("config", "user.name", "Test")
+	gitCmd("config", "user.email", "test@example.com")
+	writeFile(t, dir, "a.go", "package a\n")
+	writeFile(t, dir, "README.md", "readme\n")
+	writeFile(t, dir, "unstaged.go", "package a\n")
+	gitCmd("add", "a.go", "README.md")
+
+	s := NewService(dir)
+	staged, err := s.GetStagedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range staged {
+		paths = append(paths, f.Path)
+	}
+	if len(paths) != 2 || paths[0] != "README.md" || paths[1] != "a.go" {
+		t.Errorf("GetStagedFiles = %q, want README.md and a.go", paths)
+	}
+
+	message := "Add a\n\nWith a body.\n"
+	if out, err := s.Commit(message, false); err != nil {
+		t.Fatalf("Commit: %v\n%s", err, out)
+	}
+	if got, err := s.HeadMessage(); err != nil || got != "Add a\n\nWith a body." {
+		t.Errorf("HeadMessage = %q, %v", got, err)
+	}
+
+	if out, err := s.Commit("Add a and more", true); err != nil {
+		t.Fatalf("amend: %v\n%s", err, out)
+	}
+	if got := gitCmd("rev-list", "--count", "HEAD"); got != "1\n" {
+		t.Errorf("amend made %q commits, want 1", got)
+	}
+	if got, err := s.HeadMessage(); err != nil || got != "Add a and more" {
+		t.Errorf("HeadMessage after amend = %q, %v", got, err)
+	}
+
+	hook := filepath.Join(dir, ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho lint failed\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gitCmd("add", "unstaged.go")
+	if out, err := s.Commit("Add more", false); err == nil || out != "lint failed" {
+		t.Errorf("failing hook: Commit = %q, %v, want the hook's output", out, err)
+	}
+}
@@ -0,0 +1,275 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Conflict markers as written by git with the default marker size.
+const (
+	markerOurs   = "<<<<<<<"
+	markerBase   = "|||||||"
+	markerSep    = "======="
+	markerTheirs = ">>>>>>>"
+)
+
+// ConflictSide selects which version of a conflict region to keep.
+type ConflictSide int
+
+const (
+	SideOurs ConflictSide = iota
+	SideTheirs
+	// SideBoth keeps ours followed by theirs.
+	SideBoth
+)
+
+// ConflictRegion is one block between conflict markers in the working tree
+// version of a file.
+type ConflictRegion struct {
+	// Start and End are the 0-based indexes of the <<<<<<< and >>>>>>>
+	// marker lines.
+	Start       int
+	End         int
+	OursLabel   string
+	TheirsLabel string
+	Ours        []string
+	Base        []string
+	Theirs      []string
+}
+
+// Conflict is a file with merge conflicts.
+type Conflict struct {
+	Path   string
+	Status string
+	// Lines is the working tree content, markers included.
+	Lines   []string
+	Regions []ConflictRegion
+}
+
+// DeletedBy reports which side deleted the file in a modify/delete
+// conflict: "us" for DU, "them" for UD and "both" for DD, or "" if the file
+// exists on both sides. Such conflicts have no markers; they are resolved
+// by keeping or removing the whole file.
+func (c *Conflict) DeletedBy() string {
+	switch c.Status {
+	case "DU":
+		return "us"
+	case "UD":
+		return "them"
+	case "DD":
+		return "both"
+	}
+	return ""
+}
+
+// GetConflict reads the conflict regions of an unmerged file. Base lines
+// come from the markers when the file uses the diff3 conflict style, and
+// otherwise are reconstructed from the index stages.
+func (s *Service) GetConflict(file FileStatus) (*Conflict, error) {
+	content, err := os.ReadFile(filepath.Join(s.repoPath, file.Path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	conflict := &Conflict{
+		Path:   file.Path,
+		Status: file.Status,
+		Lines:  splitLines(string(content)),
+	}
+	conflict.Regions = parseConflictMarkers(conflict.Lines)
+
+	var stageRegions []ConflictRegion
+	for i := range conflict.Regions {
+		r := &conflict.Regions[i]
+		if r.Base != nil {
+			continue
+		}
+		if stageRegions == nil {
+			stageRegions = s.stageConflictRegions(file.Path)
+		}
+		r.Base = reconstructBase(r.Ours, stageRegions)
+	}
+	return conflict, nil
+}
+
+// parseConflictMarkers finds the conflict regions in lines.
+func parseConflictMarkers(lines []string) []ConflictRegion {
+	const (
+		outside = iota
+		inOurs
+		inBase
+		inTheirs
+	)
+
+	var regions []ConflictRegion
+	var current ConflictRegion
+	state := outside
+	for i, line := range lines {
+		switch {
+		case state == outside && strings.HasPrefix(line, markerOurs):
+			current = ConflictRegion{
+				Start:     i,
+				OursLabel: strings.TrimSpace(strings.TrimPrefix(line, markerOurs)),
+			}
+			state = inOurs
+		case state == inOurs && strings.HasPrefix(line, markerBase):
+			current.Base = []string{}
+			state = inBase
+		case (state == inOurs || state == inBase) && line == markerSep:
+			state = inTheirs
+		case state == inTheirs && strings.HasPrefix(line, markerTheirs):
+			current.End = i
+			current.TheirsLabel = strings.TrimSpace(strings.TrimPrefix(line, markerTheirs))
+			regions = append(regions, current)
+			state = outside
+		case state == inOurs:
+			current.Ours = append(current.Ours, line)
+		case state == inBase:
+			current.Base = append(current.Base, line)
+		case state == inTheirs:
+			current.Theirs = append(current.Theirs, line)
+		}
+	}
+	return regions
+}
+
+// stageConflictRegions re-merges the index stages (:1: base, :2: ours,
+// :3: theirs) with git merge-file in diff3 style, so every region carries
+// its base lines. Missing stages, as in add/add conflicts, merge as empty.
+func (s *Service) stageConflictRegions(path string) []ConflictRegion {
+	dir, err := os.MkdirTemp("", "grua-merge-")
+	if err != nil {
+		return nil
+	}
+	defer os.RemoveAll(dir)
+
+	var files []string
+	for _, stage := range []string{"2", "1", "3"} {
+		content, _ := s.run("show", ":"+stage+":"+path)
+		name := filepath.Join(dir, stage)
+		if err := os.WriteFile(name, content, 0o600); err != nil {
+			return nil
+		}
+		files = append(files, name)
+	}
+
+	args := []string{"merge-file", "-p", "--diff3", "-L", "ours", "-L", "base", "-L", "theirs"}
+	output, err := s.run(append(args, files...)...)
+	// merge-file exits with the number of conflicts, so a positive exit
+	// status still carries a usable result.
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() >= 128) {
+		return nil
+	}
+	return parseConflictMarkers(splitLines(string(output)))
+}
+
+// reconstructBase derives the base lines of a region that was written
+// without them. git merge can join adjacent conflicts into one region, so
+// each stage region whose ours lines appear inside the region contributes
+// its base lines in their place; the lines around them are unchanged on
+// our side and taken as-is.
+func reconstructBase(ours []string, stageRegions []ConflictRegion) []string {
+	base := []string{}
+	next := 0
+	for i := 0; i < len(ours); {
+		matched := false
+		for j := next; j < len(stageRegions); j++ {
+			r := stageRegions[j]
+			if len(r.Ours) > 0 && i+len(r.Ours) <= len(ours) &&
+				slices.Equal(ours[i:i+len(r.Ours)], r.Ours) {
+				base = append(base, r.Base...)
+				i += len(r.Ours)
+				next = j + 1
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			base = append(base, ours[i])
+			i++
+		}
+	}
+	return base
+}
+
+// ResolveConflict replaces region in the working tree file with the chosen
+// side. Once no conflict markers remain the file is staged, which marks it
+// resolved; staged reports whether that happened.
+func (s *Service) ResolveConflict(path string, region ConflictRegion, side ConflictSide) (staged bool, err error) {
+	fullPath := filepath.Join(s.repoPath, path)
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return false, err
+	}
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return false, err
+	}
+	lines := splitLines(string(content))
+
+	// Make sure the file still has this region where we saw it.
+	var current *ConflictRegion
+	regions := parseConflictMarkers(lines)
+	for i := range regions {
+		if regions[i].Start == region.Start && regions[i].End == region.End &&
+			slices.Equal(regions[i].Ours, region.Ours) &&
+			slices.Equal(regions[i].Theirs, region.Theirs) {
+			current = &regions[i]
+			break
+		}
+	}
+	if current == nil {
+		return false, fmt.Errorf("%s changed on disk; reload and try again", path)
+	}
+
+	var chosen []string
+	switch side {
+	case SideOurs:
+		chosen = current.Ours
+	case SideTheirs:
+		chosen = current.Theirs
+	case SideBoth:
+		chosen = append(slices.Clone(current.Ours), current.Theirs...)
+	}
+
+	resolved := slices.Concat(lines[:current.Start], chosen, lines[current.End+1:])
+	output := strings.Join(resolved, "\n")
+	if len(resolved) > 0 {
+		output += "\n"
+	}
+	if err := os.WriteFile(fullPath, []byte(output), info.Mode().Perm()); err != nil {
+		return false, err
+	}
+
+	if len(regions) > 1 {
+		return false, nil
+	}
+	return true, s.StageFile(path)
+}
+
+// StageFile adds the working tree version of path to the index.
+func (s *Service) StageFile(path string) error {
+	cmd := exec.Command("git", "add", "--", path)
+	cmd.Dir = s.repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git add %s: %s", path, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// RemoveFile deletes path from the index and the working tree. For an
+// unmerged path this resolves the conflict in favour of the deletion.
+func (s *Service) RemoveFile(path string) error {
+	cmd := exec.Command("git", "rm", "-q", "--", path)
+	cmd.Dir = s.repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git rm %s: %s", path, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
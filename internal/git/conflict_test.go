@@ -0,0 +1,163 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseConflictMarkers(t *testing.T) {
+	lines := []string{
+		"a",
+		"<<<<<<< HEAD",
+		"ours",
+		"=======",
+		"theirs",
+		">>>>>>> feature",
+		"b",
+		"<<<<<<< HEAD",
+		"|||||||  merged common ancestors",
+		"base",
+		"=======",
+		">>>>>>> feature",
+		"<<<<<<< unterminated",
+		"x",
+	}
+	want := []ConflictRegion{
+		{Start: 1, End: 5, OursLabel: "HEAD", TheirsLabel: "feature",
+			Ours: []string{"ours"}, Theirs: []string{"theirs"}},
+		{Start: 7, End: 11, OursLabel: "HEAD", TheirsLabel: "feature",
+			Base: []string{"base"}},
+	}
+	if got := parseConflictMarkers(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseConflictMarkers =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// An empty diff3 base section is still a base, unlike a missing one.
+	got := parseConflictMarkers([]string{"<<<<<<< a", "|||||||", "=======", ">>>>>>> b"})
+	if len(got) != 1 || got[0].Base == nil {
+		t.Errorf("empty base = %+v, want a non-nil base", got)
+	}
+}
+
+func TestReconstructBase(t *testing.T) {
+	// Two conflicts joined into one region with an unchanged line between.
+	ours := []string{"one", "same", "two"}
+	stages := []ConflictRegion{
+		{Ours: []string{"one"}, Base: []string{"ONE", "1"}},
+		{Ours: []string{"two"}, Base: []string{}},
+	}
+	want := []string{"ONE", "1", "same"}
+	if got := reconstructBase(ours, stages); !slices.Equal(got, want) {
+		t.Errorf("reconstructBase = %q, want %q", got, want)
+	}
+	if got := reconstructBase(nil, nil); got == nil || len(got) != 0 {
+		t.Errorf("reconstructBase(nil) = %#v, want an empty base", got)
+	}
+}
+
+func TestConflictResolution(t *testing.T) {
+	// git joins conflicts separated by only a few lines into one region.
+	const mid = "m1\nm2\nm3\nm4\n"
+	dir, gitCmd := testRepo(t)
+	writeFile(t, dir, "f.txt", "a\nbase1\n"+mid+"base2\nz\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "base")
+	gitCmd("checkout", "-q", "-b", "feature")
+	writeFile(t, dir, "f.txt", "a\ntheirs1\n"+mid+"theirs2\nz\n")
+	gitCmd("commit", "-q", "-am", "feature")
+	gitCmd("checkout", "-q", "-")
+	writeFile(t, dir, "f.txt", "a\nours1\n"+mid+"ours2\nz\n")
+	gitCmd("commit", "-q", "-am", "ours")
+	merge := exec.Command("git", "-c", "user.name=Test", "-c", "user.email=test@example.com", "merge", "feature")
+	merge.Dir = dir
+	if err := merge.Run(); err == nil {
+		t.Fatal("merge succeeded, want a conflict")
+	}
+
+	s := NewService(dir)
+	file := FileStatus{Path: "f.txt", Status: "UU", Unmerged: true}
+	conflict, err := s.GetConflict(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflict.Regions) != 2 {
+		t.Fatalf("regions = %+v, want 2", conflict.Regions)
+	}
+	for i, want := range []string{"base1", "base2"} {
+		if r := conflict.Regions[i]; !slices.Equal(r.Base, []string{want}) {
+			t.Errorf("region %d base = %q, want %q", i, r.Base, want)
+		}
+	}
+
+	staged, err := s.ResolveConflict("f.txt", conflict.Regions[1], SideTheirs)
+	if err != nil || staged {
+		t.Fatalf("first resolution: staged = %v, err = %v", staged, err)
+	}
+	// The first region has not moved, but a stale second region is refused.
+	if _, err := s.ResolveConflict("f.txt", conflict.Regions[1], SideOurs); err == nil {
+		t.Error("resolving a stale region: got no error")
+	}
+	staged, err = s.ResolveConflict("f.txt", conflict.Regions[0], SideBoth)
+	if err != nil || !staged {
+		t.Fatalf("last resolution: staged = %v, err = %v", staged, err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "f.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a\nours1\ntheirs1\n" + mid + "theirs2\nz\n"; string(content) != want {
+		t.Errorf("resolved file = %q, want %q", content, want)
+	}
+	if out := gitCmd("diff", "--name-only", "--diff-filter=U"); out != "" {
+		t.Errorf("still unmerged: %q", out)
+	}
+}
+
+func TestDeletionConflict(t *testing.T) {
+	dir, gitCmd := testRepo(t)
+	writeFile(t, dir, "f.go", "package f\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "base")
+	gitCmd("checkout", "-q", "-b", "feature")
+	writeFile(t, dir, "f.go", "package f\n\nvar x = 1\n")
+	gitCmd("commit", "-q", "-am", "modify")
+	gitCmd("checkout", "-q", "-")
+	gitCmd("rm", "-q", "f.go")
+	gitCmd("commit", "-q", "-m", "delete")
+	merge := exec.Command("git", "-c", "user.name=Test", "-c", "user.email=test@example.com", "merge", "feature")
+	merge.Dir = dir
+	if err := merge.Run(); err == nil {
+		t.Fatal("merge succeeded, want a conflict")
+	}
+
+	s := NewService(dir)
+	files, err := s.GetChangedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Status != "DU" {
+		t.Fatalf("files = %+v, want f.go deleted by us", files)
+	}
+	conflict, err := s.GetConflict(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflict.Regions) != 0 || conflict.DeletedBy() != "us" {
+		t.Fatalf("regions = %+v, DeletedBy = %q, want no regions, deleted by us", conflict.Regions, conflict.DeletedBy())
+	}
+
+	if err := s.RemoveFile("f.go"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "f.go")); !os.IsNotExist(err) {
+		t.Errorf("f.go still exists: %v", err)
+	}
+	if out := gitCmd("status", "--porcelain"); out != "" {
+		t.Errorf("status = %q, want the deletion resolved", out)
+	}
+}
@@ -0,0 +1,266 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LineRange is an inclusive range of line numbers in the new version of a
+// file.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Counts returns the number of old and new lines the hunk covers.
+func (h *Hunk) Counts() (oldCount, newCount int) {
+	for _, line := range h.Lines {
+		switch line.Type {
+		case LineAdded:
+			newCount++
+		case LineRemoved:
+			oldCount++
+		default:
+			oldCount++
+			newCount++
+		}
+	}
+	return oldCount, newCount
+}
+
+// NewRange returns the new-file lines the hunk spans. For a hunk that only
+// removes lines, end is start-1 and start is the line following the removal.
+func (h *Hunk) NewRange() (start, end int) {
+	_, newCount := h.Counts()
+	start = h.NewStart
+	if newCount == 0 {
+		start++
+	}
+	return start, start + newCount - 1
+}
+
+// GetNewContent returns the lines of the new side of a diff: the index for
+// staged diffs, otherwise the working tree.
+func (s *Service) GetNewContent(path string, staged bool) ([]string, error) {
+	var content []byte
+	var err error
+	if staged {
+		content, err = s.run("show", ":"+path)
+	} else {
+		content, err = os.ReadFile(filepath.Join(s.repoPath, path))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(string(content)), nil
+}
+
+// GetRevisionContent returns the lines of a file as of a commit.
+func (s *Service) GetRevisionContent(rev, path string) ([]string, error) {
+	content, err := s.run("show", rev+":"+path)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(string(content)), nil
+}
+
+func splitLines(content string) []string {
+	lines := strings.Split(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// ExpandContext returns a copy of diff in which the new-file lines covered
+// by ranges are shown as context. Unchanged lines are identical on both
+// sides, so only the new side is needed; old line numbers are derived from
+// the offset left by the preceding hunk. Hunks that end up touching are
+// merged.
+func ExpandContext(diff *FileDiff, newLines []string, ranges []LineRange) *FileDiff {
+	if diff == nil || len(ranges) == 0 || len(newLines) == 0 {
+		return diff
+	}
+
+	type block struct {
+		hunk     Hunk
+		from, to int
+	}
+
+	var blocks []block
+	for _, h := range diff.Hunks {
+		from, to := h.NewRange()
+		blocks = append(blocks, block{hunk: h, from: from, to: to})
+	}
+
+	// Fill the gaps before, between and after hunks with revealed lines.
+	gapStart, delta := 1, 0
+	var revealed []block
+	for i := 0; i <= len(diff.Hunks); i++ {
+		gapEnd := len(newLines)
+		if i < len(diff.Hunks) {
+			gapEnd = blocks[i].from - 1
+		}
+		for _, r := range ranges {
+			from := max(r.Start, gapStart)
+			to := min(r.End, gapEnd)
+			if from > to {
+				continue
+			}
+			h := Hunk{OldStart: from + delta, NewStart: from}
+			for n := from; n <= to; n++ {
+				h.Lines = append(h.Lines, DiffLine{
+					Content:    newLines[n-1],
+					Type:       LineContext,
+					OldLineNum: n + delta,
+					NewLineNum: n,
+				})
+			}
+			revealed = append(revealed, block{hunk: h, from: from, to: to})
+		}
+		if i < len(diff.Hunks) {
+			h := diff.Hunks[i]
+			oldCount, newCount := h.Counts()
+			oldStart := h.OldStart
+			if oldCount == 0 {
+				oldStart++
+			}
+			gapStart = blocks[i].to + 1
+			delta = (oldStart + oldCount) - (blocks[i].from + newCount)
+		}
+	}
+	blocks = append(blocks, revealed...)
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].from < blocks[j].from })
+
+	// Keep the file metadata; only the hunks are rebuilt.
+	expanded := *diff
+	expanded.Hunks = nil
+	var current *block
+	for _, b := range blocks {
+		if current != nil && b.from <= current.to+1 {
+			current.hunk.Lines = append(current.hunk.Lines, b.hunk.Lines...)
+			current.to = max(current.to, b.to)
+			continue
+		}
+		if current != nil {
+			expanded.Hunks = append(expanded.Hunks, finishHunk(current.hunk))
+		}
+		b.hunk.Lines = append([]DiffLine(nil), b.hunk.Lines...)
+		current = &b
+	}
+	if current != nil {
+		expanded.Hunks = append(expanded.Hunks, finishHunk(current.hunk))
+	}
+	return &expanded
+}
+
+// finishHunk rewrites a hunk's header after its lines have changed, keeping
+// any section heading git put after the range.
+func finishHunk(h Hunk) Hunk {
+	for _, line := range h.Lines {
+		if line.Type != LineAdded {
+			h.OldStart = line.OldLineNum
+			break
+		}
+	}
+	for _, line := range h.Lines {
+		if line.Type != LineRemoved {
+			h.NewStart = line.NewLineNum
+			break
+		}
+	}
+
+	section := ""
+	if parts := strings.SplitN(h.Header, "@@", 3); len(parts) == 3 {
+		section = parts[2]
+	}
+	oldCount, newCount := h.Counts()
+	h.Header = fmt.Sprintf("@@ -%d,%d +%d,%d @@%s", h.OldStart, oldCount, h.NewStart, newCount, section)
+	return h
+}
+
+// GetAddedLines returns the lines of a file's working tree version that
+// are new since HEAD, whether staged or not. Unversioned files, and all
+// files before the first commit, are new throughout.
+func (s *Service) GetAddedLines(file FileStatus) ([]LineRange, error) {
+	whole := []LineRange{{Start: 1, End: math.MaxInt}}
+	if file.Unversioned {
+		return whole, nil
+	}
+	if _, err := s.run("rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		return whole, nil
+	}
+
+	args := []string{"diff", "--no-color", "-M", "-U0", "HEAD", "--", file.Path}
+	if file.OldPath != "" {
+		args = append(args, file.OldPath)
+	}
+	output, err := s.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := s.parseDiff(file.Path, false, bytes.NewReader(output), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []LineRange
+	for _, hunk := range diff.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type != LineAdded {
+				continue
+			}
+			if n := len(ranges); n > 0 && ranges[n-1].End == line.NewLineNum-1 {
+				ranges[n-1].End = line.NewLineNum
+			} else {
+				ranges = append(ranges, LineRange{Start: line.NewLineNum, End: line.NewLineNum})
+			}
+		}
+	}
+	return ranges, nil
+}
+
+// WorkingTreeLine returns the line of the working tree version of path
+// that line of its version at rev has become. A line that has since been
+// changed or removed maps to the first line now in its place.
+func (s *Service) WorkingTreeLine(rev, path string, line int) (int, error) {
+	if _, err := os.Stat(filepath.Join(s.repoPath, path)); err != nil {
+		return 0, fmt.Errorf("%s is not in the working tree", path)
+	}
+	output, err := s.run("diff", "--no-color", "-U0", rev, "--", path)
+	if err != nil {
+		return 0, err
+	}
+	diff, err := s.parseDiff(path, false, bytes.NewReader(output), 0)
+	if err != nil {
+		return 0, err
+	}
+	return mapLine(diff.Hunks, line), nil
+}
+
+// mapLine follows an old line number through the hunks of a diff without
+// context to the new side.
+func mapLine(hunks []Hunk, line int) int {
+	delta := 0
+	for _, h := range hunks {
+		oldCount, newCount := h.Counts()
+		oldStart := h.OldStart
+		if oldCount == 0 {
+			oldStart++
+		}
+		if line < oldStart {
+			break
+		}
+		newStart, _ := h.NewRange()
+		if line < oldStart+oldCount {
+			return max(newStart, 1)
+		}
+		delta = (newStart + newCount) - (oldStart + oldCount)
+	}
+	return max(line+delta, 1)
+}
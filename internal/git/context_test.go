@@ -0,0 +1,171 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHunkNewRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		hunk       Hunk
+		start, end int
+	}{
+		{
+			name: "context and changes",
+			hunk: Hunk{NewStart: 4, Lines: []DiffLine{
+				{Type: LineContext}, {Type: LineRemoved}, {Type: LineAdded}, {Type: LineAdded},
+			}},
+			start: 4, end: 6,
+		},
+		{
+			name:  "removal only",
+			hunk:  Hunk{OldStart: 3, NewStart: 2, Lines: []DiffLine{{Type: LineRemoved}}},
+			start: 3, end: 2,
+		},
+	}
+	for _, tt := range tests {
+		if start, end := tt.hunk.NewRange(); start != tt.start || end != tt.end {
+			t.Errorf("%s: NewRange = %d, %d, want %d, %d", tt.name, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+// expandTestDiff adds line "added" after line 4 of a nine-line file, so
+// old line numbers after it are one less than new ones.
+func expandTestDiff() (*FileDiff, []string) {
+	var newLines []string
+	for i := 1; i <= 10; i++ {
+		newLines = append(newLines, fmt.Sprintf("l%d", i))
+	}
+	newLines[4] = "added"
+	diff := &FileDiff{
+		Path: "a.go",
+		Hunks: []Hunk{{
+			Header:   "@@ -4,2 +4,3 @@ func f() {",
+			OldStart: 4,
+			NewStart: 4,
+			Lines: []DiffLine{
+				{Content: "l4", Type: LineContext, OldLineNum: 4, NewLineNum: 4},
+				{Content: "added", Type: LineAdded, NewLineNum: 5},
+				{Content: "l6", Type: LineContext, OldLineNum: 5, NewLineNum: 6},
+			},
+		}},
+	}
+	return diff, newLines
+}
+
+func TestExpandContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		ranges  []LineRange
+		headers []string
+		first   DiffLine
+	}{
+		{
+			name:    "separate block above",
+			ranges:  []LineRange{{Start: 1, End: 2}},
+			headers: []string{"@@ -1,2 +1,2 @@", "@@ -4,2 +4,3 @@ func f() {"},
+			first:   DiffLine{Content: "l1", Type: LineContext, OldLineNum: 1, NewLineNum: 1},
+		},
+		{
+			name:    "merged with the hunk above",
+			ranges:  []LineRange{{Start: 2, End: 3}},
+			headers: []string{"@@ -2,4 +2,5 @@"},
+			first:   DiffLine{Content: "l2", Type: LineContext, OldLineNum: 2, NewLineNum: 2},
+		},
+		{
+			name:    "below, offset by the added line",
+			ranges:  []LineRange{{Start: 8, End: 20}},
+			headers: []string{"@@ -4,2 +4,3 @@ func f() {", "@@ -7,3 +8,3 @@"},
+			first:   DiffLine{Content: "l4", Type: LineContext, OldLineNum: 4, NewLineNum: 4},
+		},
+		{
+			name:    "whole file",
+			ranges:  []LineRange{{Start: 1, End: 10}},
+			headers: []string{"@@ -1,9 +1,10 @@"},
+			first:   DiffLine{Content: "l1", Type: LineContext, OldLineNum: 1, NewLineNum: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, newLines := expandTestDiff()
+			expanded := ExpandContext(diff, newLines, tt.ranges)
+			var headers []string
+			for _, h := range expanded.Hunks {
+				headers = append(headers, h.Header)
+			}
+			if fmt.Sprint(headers) != fmt.Sprint(tt.headers) {
+				t.Errorf("headers = %q, want %q", headers, tt.headers)
+			}
+			if got := expanded.Hunks[0].Lines[0]; got != tt.first {
+				t.Errorf("first line = %+v, want %+v", got, tt.first)
+			}
+			if len(diff.Hunks) != 1 || len(diff.Hunks[0].Lines) != 3 {
+				t.Error("the original diff was modified")
+			}
+		})
+	}
+}
+
+func TestExpandContextWholeFileLines(t *testing.T) {
+	diff, newLines := expandTestDiff()
+	expanded := ExpandContext(diff, newLines, []LineRange{{Start: 1, End: 10}})
+	lines := expanded.Hunks[0].Lines
+	if len(lines) != 10 {
+		t.Fatalf("got %d lines, want 10", len(lines))
+	}
+	for i, line := range lines {
+		newNum, oldNum := i+1, i+1
+		if i >= 5 {
+			oldNum = i
+		}
+		if line.Type == LineAdded {
+			if line.NewLineNum != 5 {
+				t.Errorf("added line at %d, want 5", line.NewLineNum)
+			}
+			continue
+		}
+		if line.NewLineNum != newNum || line.OldLineNum != oldNum {
+			t.Errorf("line %d = -%d +%d, want -%d +%d", i, line.OldLineNum, line.NewLineNum, oldNum, newNum)
+		}
+	}
+}
+
+func TestWorkingTreeLine(t *testing.T) {
+	dir, gitCmd := testRepo(t)
+	writeFile(t, dir, "a.go", "a\nb\nc\nd\ne\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "init")
+	rev := strings.TrimSpace(gitCmd("rev-parse", "HEAD"))
+	// Two lines inserted after a, and d changed.
+	writeFile(t, dir, "a.go", "a\nX\nY\nb\nc\nD\ne\n")
+
+	s := NewService(dir)
+	for line, want := range map[int]int{1: 1, 2: 4, 3: 5, 4: 6, 5: 7} {
+		got, err := s.WorkingTreeLine(rev, "a.go", line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("line %d maps to %d, want %d", line, got, want)
+		}
+	}
+	if _, err := s.WorkingTreeLine(rev, "gone.go", 1); err == nil {
+		t.Error("missing file: got no error")
+	}
+}
+
+func TestMapLine(t *testing.T) {
+	// Lines 3 and 4 removed, then a line added after old line 8.
+	hunks := []Hunk{
+		{OldStart: 3, NewStart: 2, Lines: []DiffLine{{Type: LineRemoved}, {Type: LineRemoved}}},
+		{OldStart: 8, NewStart: 7, Lines: []DiffLine{{Type: LineAdded}}},
+	}
+	for line, want := range map[int]int{2: 2, 3: 3, 4: 3, 5: 3, 8: 6, 9: 8} {
+		if got := mapLine(hunks, line); got != want {
+			t.Errorf("mapLine(%d) = %d, want %d", line, got, want)
+		}
+	}
+}
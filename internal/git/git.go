@@ -2,8 +2,10 @@ package git
 
 import (
 	"bufio"
-	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -15,12 +17,33 @@ type FileStatus struct {
 	Status      string
 	Staged      bool
 	Unversioned bool
+	Deleted     bool
+	// Unmerged is set for paths with merge conflicts; Status then holds
+	// both sides of the conflict, e.g. "UU" or "AA".
+	Unmerged bool
+	// Submodule describes the state of a submodule, empty for plain files.
+	Submodule string
+	Binary    bool
+	// Large is set when the diff is long enough to be capped.
+	Large bool
+	// OldPath is the source of a rename or copy.
+	OldPath string
+	// Similarity is git's similarity score (0-100) for a rename or copy.
+	Similarity int
+	// OldMode and NewMode are set when the file mode changed.
+	OldMode string
+	NewMode string
+	// Commit is set for files changed by a commit rather than in the
+	// working tree, and holds the commit hash.
+	Commit string
 }
 
 // Hunk represents a diff hunk.
 type Hunk struct {
-	Header string
-	Lines  []DiffLine
+	Header   string
+	Lines    []DiffLine
+	OldStart int
+	NewStart int
 }
 
 // DiffLine represents a single line in a diff.
@@ -29,6 +52,9 @@ type DiffLine struct {
 	Type       LineType
 	OldLineNum int
 	NewLineNum int
+	// Moved is set on added and removed lines that belong to a block moved
+	// elsewhere in the file rather than changed.
+	Moved bool
 }
 
 type LineType int
@@ -43,7 +69,65 @@ const (
 type FileDiff struct {
 	Path   string
 	Staged bool
+	// Commit is the hash of the commit the diff belongs to, if any.
+	Commit string
 	Hunks  []Hunk
+
+	// Metadata from the extended header lines of git diff.
+	OldPath    string
+	Similarity int
+	Renamed    bool
+	Copied     bool
+	NewFile    bool
+	Deleted    bool
+	OldMode    string
+	NewMode    string
+
+	// Binary diffs have no hunks; Summary describes both sides instead.
+	Binary  bool
+	Summary *BlobSummary
+	// Truncated is set when the diff was capped at DiffOptions.MaxLines.
+	// TotalLines is the number of diff lines read, which for a truncated
+	// git diff stops at the cap.
+	Truncated  bool
+	TotalLines int
+}
+
+// ModeChanged reports whether the diff changes the file mode of an existing
+// file.
+func (d *FileDiff) ModeChanged() bool {
+	return d.OldMode != "" && d.NewMode != "" && d.OldMode != d.NewMode
+}
+
+// DiffOptions controls how diffs are generated.
+type DiffOptions struct {
+	// Context is the number of unchanged lines shown around each change
+	// (git diff -U).
+	Context int
+	// IgnoreWhitespace ignores whitespace when comparing lines (git diff -w).
+	IgnoreWhitespace bool
+	// IgnoreBlankLines ignores changes whose lines are all blank.
+	IgnoreBlankLines bool
+	// DetectMoves marks blocks of lines that were moved rather than changed.
+	DetectMoves bool
+	// MaxLines caps the number of diff lines loaded; 0 means no limit.
+	MaxLines int
+}
+
+// DefaultDiffOptions returns the options matching plain git diff.
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{Context: 3, MaxLines: LargeDiffLines}
+}
+
+func (o DiffOptions) args() []string {
+	args := []string{fmt.Sprintf("-U%d", max(o.Context, 0))}
+	if o.IgnoreWhitespace {
+		args = append(args, "-w")
+	}
+	if o.IgnoreBlankLines {
+		args = append(args, "--ignore-blank-lines")
+	}
+	return args
 }
 
 // Service provides git operations.
@@ -55,122 +139,206 @@ func NewService(repoPath string) *Service {
 	return &Service{repoPath: repoPath}
 }
 
-// GetChangedFiles returns all changed .go files (both staged and unstaged).
-func (s *Service) GetChangedFiles() ([]FileStatus, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
+// run executes a git command in the repository and returns its stdout.
+func (s *Service) run(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
 	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	return cmd.Output()
+}
+
+// GetChangedFiles returns all changed .go, go.mod and go.sum files (both
+// staged and unstaged). Renames and copies are kept if either side is one.
+func (s *Service) GetChangedFiles() ([]FileStatus, error) {
+	return s.changedFiles(func(e statusEntry) bool {
+		return isGoFile(e.Path) || isGoFile(e.OrigPath)
+	})
+}
+
+// isGoFile reports whether path is Go source or module metadata.
+func isGoFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasSuffix(name, ".go") || name == "go.mod" || name == "go.sum"
+}
+
+// GetAllChangedFiles returns all changed files, of any type.
+func (s *Service) GetAllChangedFiles() ([]FileStatus, error) {
+	return s.changedFiles(func(statusEntry) bool { return true })
+}
+
+func (s *Service) changedFiles(keep func(statusEntry) bool) ([]FileStatus, error) {
+	entries, err := s.getStatus()
 	if err != nil {
 		return nil, err
 	}
 
 	var files []FileStatus
-	scanner := bufio.NewScanner(bytes.NewReader(output))
-	for scanner.Scan() {
-		line := scanner.Text()
-		if len(line) < 4 {
-			continue
-		}
-
-		// Porcelain format: XY PATH (X=staged, Y=unstaged)
-		stagedStatus := line[0]
-		unstagedStatus := line[1]
-		path := strings.TrimSpace(line[3:])
-
-		if strings.Contains(path, " -> ") {
-			parts := strings.Split(path, " -> ")
-			path = parts[1]
+	for _, e := range entries {
+		if keep(e) {
+			files = append(files, e.files()...)
 		}
+	}
 
-		if !strings.HasSuffix(path, ".go") {
-			continue
-		}
-
-		if stagedStatus == '?' && unstagedStatus == '?' {
-			files = append(files, FileStatus{
-				Path:        path,
-				Status:      "N",
-				Unversioned: true,
-			})
-		} else {
-			if stagedStatus != ' ' && stagedStatus != '?' {
-				files = append(files, FileStatus{
-					Path:   path,
-					Status: string(stagedStatus),
-					Staged: true,
-				})
-			}
-
-			if unstagedStatus != ' ' && unstagedStatus != '?' {
-				files = append(files, FileStatus{
-					Path:   path,
-					Status: string(unstagedStatus),
-					Staged: false,
-				})
-			}
-		}
+	if err := s.addSizeDetails(files); err != nil {
+		return nil, err
 	}
+	return files, nil
+}
 
-	return files, scanner.Err()
+func (s *Service) getStatus() ([]statusEntry, error) {
+	output, err := s.run("status", "--porcelain=v2", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	return parseStatusV2(output)
 }
 
-// GetDiff returns the diff for a specific file.
-func (s *Service) GetDiff(path string, staged bool) (*FileDiff, error) {
-	args := []string{"diff", "--no-color"}
+// GetDiff returns the diff for a changed file. Renames and copies are
+// diffed against their source path.
+func (s *Service) GetDiff(file FileStatus, opts DiffOptions) (*FileDiff, error) {
+	path, staged := file.Path, file.Staged
+	args := []string{"diff", "--no-color", "-M", "-C"}
+	args = append(args, opts.args()...)
 	if staged {
 		args = append(args, "--staged")
 	}
 	args = append(args, "--", path)
+	if file.OldPath != "" {
+		args = append(args, file.OldPath)
+	}
 
+	diff, err := s.streamDiff(path, staged, opts.MaxLines, args)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if staged && errors.As(err, &exitErr) {
+			return s.getNewFileDiff(path, staged)
+		}
+		return nil, err
+	}
+	s.finishDiff(diff, file, opts)
+	return diff, nil
+}
+
+// streamDiff runs git with args and parses the diff as git writes it, so a
+// huge one is read no further than maxLines and git is stopped there. If
+// git fails after its output was parsed, the diff is returned along with
+// the error, so that callers can accept exit codes that are not failures.
+func (s *Service) streamDiff(path string, staged bool, maxLines int, args []string) (*FileDiff, error) {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 0 {
-			return &FileDiff{Path: path, Staged: staged}, nil
-		}
-		if staged {
-			return s.getNewFileDiff(path, staged)
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	diff, parseErr := s.parseDiff(path, staged, stdout, maxLines)
+	if diff.Truncated || parseErr != nil {
+		// Nothing more will be read, so stop git writing the rest.
+		if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return nil, err
 		}
+	}
+	waitErr := cmd.Wait()
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if waitErr != nil && !diff.Truncated {
+		return diff, waitErr
+	}
+	return diff, nil
+}
+
+// finishDiff adds what plain git diff output lacks: the summary of a binary
+// file and, when enabled, moved-block detection.
+func (s *Service) finishDiff(diff *FileDiff, file FileStatus, opts DiffOptions) {
+	if diff.Binary {
+		diff.Summary = s.getBlobSummary(file)
+	}
+	if opts.DetectMoves {
+		markMoved(diff, opts.IgnoreWhitespace)
+	}
+}
+
+// GetContentDiff diffs a working tree file against other content for the
+// same file, such as its formatted version.
+func (s *Service) GetContentDiff(path string, content []byte, opts DiffOptions) (*FileDiff, error) {
+	f, err := os.CreateTemp("", "grua-*"+filepath.Ext(path))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(content)
+	f.Close()
+	if err != nil {
 		return nil, err
 	}
 
-	return s.parseDiff(path, staged, output)
+	args := []string{"diff", "--no-index", "--no-color"}
+	args = append(args, opts.args()...)
+	args = append(args, "--", filepath.Join(s.repoPath, path), f.Name())
+	parsed, err := s.streamDiff(path, false, opts.MaxLines, args)
+	// With --no-index, git exits with 1 when the files differ.
+	if exitErr, ok := err.(*exec.ExitError); err != nil && (!ok || exitErr.ExitCode() != 1) {
+		return nil, err
+	}
+	// The temporary file's name and mode are not part of the change.
+	return &FileDiff{
+		Path:       path,
+		Hunks:      parsed.Hunks,
+		Truncated:  parsed.Truncated,
+		TotalLines: parsed.TotalLines,
+	}, nil
 }
 
-// GetUnversionedDiff returns a synthetic diff for an unversioned file (all lines as added).
-func (s *Service) GetUnversionedDiff(path string) (*FileDiff, error) {
-	fullPath := filepath.Join(s.repoPath, path)
-	output, err := exec.Command("cat", fullPath).Output()
+// GetUnversionedDiff returns a synthetic diff for an unversioned file (all
+// lines as added). Binary files get a summary instead, and the file is read
+// no further than opts.MaxLines.
+func (s *Service) GetUnversionedDiff(path string, opts DiffOptions) (*FileDiff, error) {
+	f, err := os.Open(filepath.Join(s.repoPath, path))
 	if err != nil {
 		return &FileDiff{Path: path}, nil
 	}
-
-	lines := strings.Split(string(output), "\n")
-	if len(lines) > 0 && lines[len(lines)-1] == "" {
-		lines = lines[:len(lines)-1]
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	if head, _ := reader.Peek(sniffLen); isBinary(head) {
+		return &FileDiff{
+			Path:    path,
+			NewFile: true,
+			Binary:  true,
+			Summary: s.getBlobSummary(FileStatus{Path: path, Unversioned: true}),
+		}, nil
 	}
 
+	diff := &FileDiff{Path: path, NewFile: true}
 	var diffLines []DiffLine
-	for i, line := range lines {
+	scanner := newLineScanner(reader)
+	for scanner.Scan() {
+		diff.TotalLines++
+		if opts.MaxLines > 0 && len(diffLines) >= opts.MaxLines {
+			diff.Truncated = true
+			continue
+		}
 		diffLines = append(diffLines, DiffLine{
-			Content:    line,
+			Content:    scanner.Text(),
 			Type:       LineAdded,
-			NewLineNum: i + 1,
+			NewLineNum: diff.TotalLines,
 		})
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
 
-	header := fmt.Sprintf("@@ -0,0 +1,%d @@ (unversioned file)", len(lines))
-
-	return &FileDiff{
-		Path: path,
-		Hunks: []Hunk{
-			{
-				Header: header,
-				Lines:  diffLines,
-			},
+	header := fmt.Sprintf("@@ -0,0 +1,%d @@ (unversioned file)", diff.TotalLines)
+	diff.Hunks = []Hunk{
+		{
+			Header:   header,
+			Lines:    diffLines,
+			NewStart: 1,
 		},
-	}, nil
+	}
+	return diff, nil
 }
 
 func (s *Service) getNewFileDiff(path string, staged bool) (*FileDiff, error) {
@@ -202,40 +370,61 @@ func (s *Service) getNewFileDiff(path string, staged bool) (*FileDiff, error) {
 		Staged: staged,
 		Hunks: []Hunk{
 			{
-				Header: "@@ -0,0 +1," + string(rune('0'+len(lines))) + " @@ (new file)",
-				Lines:  diffLines,
+				Header:   "@@ -0,0 +1," + string(rune('0'+len(lines))) + " @@ (new file)",
+				Lines:    diffLines,
+				NewStart: 1,
 			},
 		},
 	}, nil
 }
 
-func (s *Service) parseDiff(path string, staged bool, output []byte) (*FileDiff, error) {
+func (s *Service) parseDiff(path string, staged bool, r io.Reader, maxLines int) (*FileDiff, error) {
 	diff := &FileDiff{
 		Path:   path,
 		Staged: staged,
 	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner := newLineScanner(r)
 	var currentHunk *Hunk
 	oldLineNum := 0
 	newLineNum := 0
+	// header receives the metadata of the current section. When a rename
+	// is not detected the old path gets a section too, whose header
+	// describes that file and not this one, so it is read and dropped.
+	header := diff
 
 	for scanner.Scan() {
 		line := scanner.Text()
 
-		if strings.HasPrefix(line, "diff --git") ||
-			strings.HasPrefix(line, "index ") ||
-			strings.HasPrefix(line, "---") ||
-			strings.HasPrefix(line, "+++") {
+		// Each file section starts with its own header, which may follow
+		// the hunks of the one before.
+		if strings.HasPrefix(line, "diff --git ") {
+			if currentHunk != nil {
+				diff.Hunks = append(diff.Hunks, *currentHunk)
+				currentHunk = nil
+			}
+			header = diff
+			if !isSectionOf(line, path) {
+				header = &FileDiff{}
+			}
+			continue
+		}
+		if currentHunk == nil && parseExtendedHeader(header, line) {
 			continue
 		}
 
+		if maxLines > 0 && diff.TotalLines >= maxLines && (currentHunk != nil || strings.HasPrefix(line, "@@")) {
+			// The rest of the diff is not read.
+			diff.Truncated = true
+			break
+		}
+
 		if strings.HasPrefix(line, "@@") {
 			if currentHunk != nil {
 				diff.Hunks = append(diff.Hunks, *currentHunk)
 			}
-			currentHunk = &Hunk{Header: line}
 			oldLineNum, newLineNum = parseHunkHeader(line)
+			currentHunk = &Hunk{Header: line, OldStart: oldLineNum, NewStart: newLineNum}
 			continue
 		}
 
@@ -243,6 +432,8 @@ func (s *Service) parseDiff(path string, staged bool, output []byte) (*FileDiff,
 			continue
 		}
 
+		diff.TotalLines++
+
 		var diffLine DiffLine
 		if len(line) == 0 {
 			diffLine = DiffLine{
@@ -294,8 +485,83 @@ func (s *Service) parseDiff(path string, staged bool, output []byte) (*FileDiff,
 	return diff, scanner.Err()
 }
 
+// isSectionOf reports whether a diff --git line starts the section whose
+// new side is path. git quotes paths with unusual characters.
+func isSectionOf(line, path string) bool {
+	return strings.HasSuffix(line, " b/"+path) || strings.HasSuffix(line, ` "b/`+quotePath(path)+`"`)
+}
+
+// quotePath escapes a path the way git does when core.quotePath is set:
+// quotes and backslashes are escaped, and control characters and bytes
+// outside ASCII are written in octal.
+func quotePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&b, `\%03o`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// parseExtendedHeader records the metadata git prints before a file's
+// hunks. It returns false for lines that are not part of that header.
+func parseExtendedHeader(diff *FileDiff, line string) bool {
+	field := func(prefix string) (string, bool) {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimPrefix(line, prefix), true
+		}
+		return "", false
+	}
+
+	if strings.HasPrefix(line, "Binary files ") {
+		diff.Binary = true
+	} else if v, ok := field("old mode "); ok {
+		diff.OldMode = v
+	} else if v, ok := field("new mode "); ok {
+		diff.NewMode = v
+	} else if v, ok := field("new file mode "); ok {
+		diff.NewFile = true
+		diff.NewMode = v
+	} else if v, ok := field("deleted file mode "); ok {
+		diff.Deleted = true
+		diff.OldMode = v
+	} else if v, ok := field("similarity index "); ok {
+		diff.Similarity = parseNum(v)
+	} else if v, ok := field("rename from "); ok {
+		diff.Renamed = true
+		diff.OldPath = v
+	} else if v, ok := field("copy from "); ok {
+		diff.Copied = true
+		diff.OldPath = v
+	} else if !strings.HasPrefix(line, "index ") &&
+		!strings.HasPrefix(line, "rename to ") &&
+		!strings.HasPrefix(line, "copy to ") &&
+		!strings.HasPrefix(line, "dissimilarity index ") &&
+		!strings.HasPrefix(line, "---") &&
+		!strings.HasPrefix(line, "+++") {
+		return false
+	}
+	return true
+}
+
 // parseHunkHeader extracts line numbers from @@ -old,count +new,count @@.
+// The section heading git adds after the range is ignored, as it may hold
+// code such as "a + b".
 func parseHunkHeader(header string) (oldStart, newStart int) {
+	if parts := strings.SplitN(header, "@@", 3); len(parts) == 3 {
+		header = parts[1]
+	}
 	parts := strings.Split(header, " ")
 	for _, part := range parts {
 		if strings.HasPrefix(part, "-") && !strings.HasPrefix(part, "---") {
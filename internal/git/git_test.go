@@ -0,0 +1,271 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDiff(t *testing.T) {
+	output := `diff --git a/old.go b/new.go
+similarity index 90%
+rename from old.go
+rename to new.go
+index 1111111..2222222 100644
+--- a/old.go
++++ b/new.go
+@@ -1,3 +1,3 @@ package main
+ package main
+-var a = 1
++var a = 2
+
+@@ -10,2 +10,3 @@ func f() {
+ 	x := 1
++	y := 2
+ 	return
+`
+	diff, err := (&Service{}).parseDiff("new.go", true, strings.NewReader(output), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Renamed || diff.OldPath != "old.go" || diff.Similarity != 90 || !diff.Staged {
+		t.Errorf("header = %+v, want a staged 90%% rename from old.go", diff)
+	}
+	if len(diff.Hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(diff.Hunks))
+	}
+
+	want := []DiffLine{
+		{Content: "package main", Type: LineContext, OldLineNum: 1, NewLineNum: 1},
+		{Content: "var a = 1", Type: LineRemoved, OldLineNum: 2},
+		{Content: "var a = 2", Type: LineAdded, NewLineNum: 2},
+		{Content: "", Type: LineContext, OldLineNum: 3, NewLineNum: 3},
+	}
+	assertLines(t, diff.Hunks[0].Lines, want)
+	if h := diff.Hunks[1]; h.OldStart != 10 || h.NewStart != 10 {
+		t.Errorf("second hunk starts at -%d +%d, want -10 +10", h.OldStart, h.NewStart)
+	}
+	assertLines(t, diff.Hunks[1].Lines, []DiffLine{
+		{Content: "\tx := 1", Type: LineContext, OldLineNum: 10, NewLineNum: 10},
+		{Content: "\ty := 2", Type: LineAdded, NewLineNum: 11},
+		{Content: "\treturn", Type: LineContext, OldLineNum: 11, NewLineNum: 12},
+	})
+}
+
+func TestParseDiffSections(t *testing.T) {
+	// Diffing a path and its old path without a detected rename gives a
+	// section for each. Header lines of the second must not be read as
+	// lines of the first section's last hunk.
+	output := `diff --git a/new.go b/new.go
+new file mode 100644
+index 0000000..2222222
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1 @@
++package b
+diff --git a/old.go b/old.go
+deleted file mode 100644
+index 1111111..0000000
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package a
+`
+	diff, err := (&Service{}).parseDiff("new.go", false, strings.NewReader(output), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(diff.Hunks))
+	}
+	assertLines(t, diff.Hunks[0].Lines, []DiffLine{
+		{Content: "package b", Type: LineAdded, NewLineNum: 1},
+	})
+	assertLines(t, diff.Hunks[1].Lines, []DiffLine{
+		{Content: "package a", Type: LineRemoved, OldLineNum: 1},
+	})
+	// Only the header of new.go's own section describes it.
+	if !diff.NewFile || diff.Deleted {
+		t.Errorf("NewFile = %v, Deleted = %v, want true, false", diff.NewFile, diff.Deleted)
+	}
+
+	// The same holds when the old path's section comes first.
+	output = `diff --git a/a.go b/a.go
+deleted file mode 100644
+--- a/a.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package a
+diff --git "a/sp\303\244ce.go" "b/sp\303\244ce.go"
+new file mode 100755
+--- /dev/null
++++ "b/sp\303\244ce.go"
+@@ -0,0 +1 @@
++package b
+`
+	diff, err = (&Service{}).parseDiff("späce.go", false, strings.NewReader(output), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(diff.Hunks))
+	}
+	if !diff.NewFile || diff.Deleted || diff.NewMode != "100755" {
+		t.Errorf("NewFile = %v, Deleted = %v, NewMode = %q, want true, false, 100755", diff.NewFile, diff.Deleted, diff.NewMode)
+	}
+}
+
+func TestParseDiffTruncated(t *testing.T) {
+	output := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1,4 +1,4 @@\n a\n-b\n+c\n d\n@@ -9 +9 @@\n-e\n+f\n"
+	diff, err := (&Service{}).parseDiff("a.go", false, strings.NewReader(output), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Truncated {
+		t.Error("diff is not truncated")
+	}
+	if len(diff.Hunks) != 1 || len(diff.Hunks[0].Lines) != 3 || diff.TotalLines != 3 {
+		t.Errorf("hunks = %+v, TotalLines = %d, want one hunk of 3 lines", diff.Hunks, diff.TotalLines)
+	}
+}
+
+func TestGetDiffStopsAtCap(t *testing.T) {
+	dir, gitCmd := testRepo(t)
+	writeFile(t, dir, "big.go", "package big\n")
+	gitCmd("add", "big.go")
+	gitCmd("commit", "-q", "-m", "init")
+
+	// Far more output than a pipe buffers, so git blocks unless it is
+	// stopped once the cap is reached.
+	var b strings.Builder
+	b.WriteString("package big\n")
+	for i := range 200000 {
+		fmt.Fprintf(&b, "var v%d = %d\n", i, i)
+	}
+	writeFile(t, dir, "big.go", b.String())
+
+	diff, err := NewService(dir).GetDiff(FileStatus{Path: "big.go"}, DiffOptions{Context: 3, MaxLines: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Truncated || diff.TotalLines != 100 || len(diff.Hunks) != 1 || len(diff.Hunks[0].Lines) != 100 {
+		t.Errorf("Truncated = %v, TotalLines = %d, want a single hunk of 100 lines", diff.Truncated, diff.TotalLines)
+	}
+
+	full, err := NewService(dir).GetDiff(FileStatus{Path: "big.go"}, DiffOptions{Context: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.Truncated || full.TotalLines != 200001 {
+		t.Errorf("uncapped: Truncated = %v, TotalLines = %d, want 200001 lines", full.Truncated, full.TotalLines)
+	}
+
+	content, err := NewService(dir).GetContentDiff("big.go", []byte("package big\n"), DiffOptions{Context: 3, MaxLines: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !content.Truncated || content.TotalLines != 100 {
+		t.Errorf("content diff: Truncated = %v, TotalLines = %d, want 100 lines", content.Truncated, content.TotalLines)
+	}
+
+	gitCmd("commit", "-q", "-a", "-m", "grow")
+	hash := strings.TrimSpace(gitCmd("rev-parse", "HEAD"))
+	commit, err := NewService(dir).GetCommitDiff(FileStatus{Path: "big.go", Commit: hash}, DiffOptions{Context: 3, MaxLines: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !commit.Truncated || commit.TotalLines != 100 {
+		t.Errorf("commit diff: Truncated = %v, TotalLines = %d, want 100 lines", commit.Truncated, commit.TotalLines)
+	}
+}
+
+func TestParseHunkHeader(t *testing.T) {
+	tests := []struct {
+		header             string
+		oldStart, newStart int
+	}{
+		{"@@ -1,3 +1,4 @@", 1, 1},
+		{"@@ -12 +15,2 @@ func f() {", 12, 15},
+		{"@@ -0,0 +1 @@", 0, 1},
+		{"@@ -5,2 +5,2 @@ return a + b - c", 5, 5},
+	}
+	for _, tt := range tests {
+		oldStart, newStart := parseHunkHeader(tt.header)
+		if oldStart != tt.oldStart || newStart != tt.newStart {
+			t.Errorf("parseHunkHeader(%q) = %d, %d, want %d, %d", tt.header, oldStart, newStart, tt.oldStart, tt.newStart)
+		}
+	}
+}
+
+// testRepo creates an empty repository and returns its directory and a
+// function running git in it.
+func testRepo(t *testing.T) (string, func(args ...string) string) {
+	t.Helper()
+	dir := t.TempDir()
+	gitCmd := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return string(out)
+	}
+	gitCmd("init", "-q")
+	return dir, gitCmd
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertLines(t *testing.T, got, want []DiffLine) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i].Content != want[i].Content || got[i].Type != want[i].Type ||
+			got[i].OldLineNum != want[i].OldLineNum || got[i].NewLineNum != want[i].NewLineNum {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseExtendedHeader(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  FileDiff
+	}{
+		{[]string{"old mode 100644", "new mode 100755"}, FileDiff{OldMode: "100644", NewMode: "100755"}},
+		{[]string{"new file mode 100644", "index 0000000..1111111"}, FileDiff{NewFile: true, NewMode: "100644"}},
+		{[]string{"deleted file mode 100755"}, FileDiff{Deleted: true, OldMode: "100755"}},
+		{[]string{"similarity index 100%", "copy from a b.go", "copy to c.go"}, FileDiff{Copied: true, OldPath: "a b.go", Similarity: 100}},
+		{[]string{"dissimilarity index 80%", "--- a/a.go", "+++ b/a.go"}, FileDiff{}},
+		{[]string{"Binary files a/img.png and b/img.png differ"}, FileDiff{Binary: true}},
+	}
+	for _, tt := range tests {
+		var diff FileDiff
+		for _, line := range tt.lines {
+			if !parseExtendedHeader(&diff, line) {
+				t.Errorf("%q is not read as a header line", line)
+			}
+		}
+		if !reflect.DeepEqual(diff, tt.want) {
+			t.Errorf("%q: got %+v, want %+v", tt.lines, diff, tt.want)
+		}
+	}
+	for _, line := range []string{" context", "+added", "-removed", "@@ -1 +1 @@"} {
+		if parseExtendedHeader(&FileDiff{}, line) {
+			t.Errorf("%q is read as a header line", line)
+		}
+	}
+}
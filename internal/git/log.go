@@ -0,0 +1,161 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is an entry in the commit history or the stash list.
+type Commit struct {
+	Hash string
+	// Short is the abbreviated hash, or the stash@{n} ref of a stash entry.
+	Short   string
+	Author  string
+	Date    time.Time
+	Subject string
+}
+
+// logFields is the number of NUL-separated fields logFormat produces per
+// commit.
+const logFields = 5
+
+const logFormat = "--format=%H%x00%h%x00%an%x00%at%x00%s"
+
+// GetLog returns up to limit commits, newest first. If path is set only
+// commits touching it are listed, following renames. A repository without
+// commits has an empty history.
+func (s *Service) GetLog(path string, limit int) ([]Commit, error) {
+	if _, err := s.run("rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		return nil, nil
+	}
+
+	args := []string{"log", "-z", logFormat, fmt.Sprintf("--max-count=%d", limit)}
+	if path != "" {
+		args = append(args, "--follow", "--", path)
+	}
+	output, err := s.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(string(output)), nil
+}
+
+// parseLog splits git log -z output in logFormat. With -z, commits are
+// separated by NUL just like the fields within one.
+func parseLog(output string) []Commit {
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+	var commits []Commit
+	for i := 0; i+logFields <= len(fields); i += logFields {
+		f := fields[i : i+logFields]
+		c := Commit{
+			Hash:    f[0],
+			Short:   f[1],
+			Author:  f[2],
+			Subject: f[4],
+		}
+		if secs, err := strconv.ParseInt(f[3], 10, 64); err == nil {
+			c.Date = time.Unix(secs, 0)
+		}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+// commitParent returns what a commit is compared against: its first
+// parent, or the empty tree for a root commit.
+func (s *Service) commitParent(hash string) (string, error) {
+	if output, err := s.run("rev-parse", "--verify", "-q", hash+"^"); err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+	output, err := s.run("hash-object", "-t", "tree", "--stdin")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetCommitFiles returns the .go, go.mod and go.sum files changed by a
+// commit relative to its first parent. Renames and copies are kept if
+// either side is one.
+func (s *Service) GetCommitFiles(hash string) ([]FileStatus, error) {
+	parent, err := s.commitParent(hash)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.run("diff", "--raw", "-z", "-M", "-C", parent, hash)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.getNumstat(parent, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileStatus
+	for _, file := range parseRaw(string(output)) {
+		if !isGoFile(file.Path) && !isGoFile(file.OldPath) {
+			continue
+		}
+		file.Commit = hash
+		if st, ok := stats[file.Path]; ok {
+			file.Binary = st.binary
+			file.Large = st.added+st.removed > LargeDiffLines
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// parseRaw parses git diff --raw -z output. Each record is
+// ":<old mode> <new mode> <old hash> <new hash> <status>" followed by the
+// path, or by the old and new paths for renames and copies.
+func parseRaw(output string) []FileStatus {
+	fields := strings.Split(output, "\x00")
+	var files []FileStatus
+	for i := 0; i < len(fields); i++ {
+		meta := strings.Fields(strings.TrimPrefix(fields[i], ":"))
+		if len(meta) != 5 || i+1 >= len(fields) {
+			continue
+		}
+		status := meta[4]
+		file := FileStatus{Status: status[:1], Path: fields[i+1]}
+		i++
+		if file.Status == "R" || file.Status == "C" {
+			if i+1 >= len(fields) || fields[i+1] == "" {
+				break
+			}
+			file.OldPath, file.Path = file.Path, fields[i+1]
+			file.Similarity, _ = strconv.Atoi(status[1:])
+			i++
+		}
+		file.Deleted = file.Status == "D"
+		file.OldMode, file.NewMode = modeChange(meta[0], meta[1])
+		files = append(files, file)
+	}
+	return files
+}
+
+// GetCommitDiff returns the diff of a file changed by a commit, relative to
+// the commit's first parent.
+func (s *Service) GetCommitDiff(file FileStatus, opts DiffOptions) (*FileDiff, error) {
+	parent, err := s.commitParent(file.Commit)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"diff", "--no-color", "-M", "-C"}
+	args = append(args, opts.args()...)
+	args = append(args, parent, file.Commit, "--", file.Path)
+	if file.OldPath != "" {
+		args = append(args, file.OldPath)
+	}
+	diff, err := s.streamDiff(file.Path, false, opts.MaxLines, args)
+	if err != nil {
+		return nil, err
+	}
+	diff.Commit = file.Commit
+	s.finishDiff(diff, file, opts)
+	return diff, nil
+}
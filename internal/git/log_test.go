@@ -0,0 +1,111 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLog(t *testing.T) {
+	output := "aaaa\x00aa\x00Ann\x001700000000\x00first: a, b\x00" +
+		"bbbb\x00bb\x00Bo\x00bad\x00second\x00" +
+		"cccc\x00cc\x00"
+	want := []Commit{
+		{Hash: "aaaa", Short: "aa", Author: "Ann", Date: time.Unix(1700000000, 0), Subject: "first: a, b"},
+		{Hash: "bbbb", Short: "bb", Author: "Bo", Subject: "second"},
+	}
+	if got := parseLog(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLog =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := parseLog(""); got != nil {
+		t.Errorf("parseLog(\"\") = %+v, want nil", got)
+	}
+}
+
+func TestParseRaw(t *testing.T) {
+	output := strings.Join([]string{
+		":100644 100644 1111111 2222222 M", "a.go",
+		":000000 100644 0000000 3333333 A", "new.go",
+		":100644 000000 4444444 0000000 D", "gone.go",
+		":100644 100755 5555555 6666666 R087", "old.go", "renamed.go",
+		":100644 100644 7777777 7777777 C100", "src.go", "copy.go",
+		"stray",
+		":100644 100644 8888888 9999999 R090", "cut.go",
+	}, "\x00") + "\x00"
+	want := []FileStatus{
+		{Path: "a.go", Status: "M"},
+		{Path: "new.go", Status: "A"},
+		{Path: "gone.go", Status: "D", Deleted: true},
+		{Path: "renamed.go", OldPath: "old.go", Status: "R", Similarity: 87, OldMode: "100644", NewMode: "100755"},
+		{Path: "copy.go", OldPath: "src.go", Status: "C", Similarity: 100},
+	}
+	if got := parseRaw(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRaw =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCommitHistory(t *testing.T) {
+	dir, gitCmd := testRepo(t)
+	s := NewService(dir)
+	if commits, err := s.GetLog("", 10); err != nil || commits != nil {
+		t.Fatalf("empty repository: GetLog = %v, %v", commits, err)
+	}
+
+	writeFile(t, dir, "a.go", "package a\n")
+	writeFile(t, dir, "README", "readme\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "root")
+	gitCmd("mv", "a.go", "b.go")
+	writeFile(t, dir, "c.go", "package a\n\nvar c = 1\n")
+	writeFile(t, dir, "go.mod", "module example.com/a\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "rename")
+
+	commits, err := s.GetLog("", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "rename" || commits[1].Subject != "root" {
+		t.Fatalf("GetLog = %+v, want rename then root", commits)
+	}
+	if followed, err := s.GetLog("b.go", 10); err != nil || len(followed) != 2 {
+		t.Errorf("GetLog(b.go) = %+v, %v, want both commits through the rename", followed, err)
+	}
+	if limited, err := s.GetLog("", 1); err != nil || len(limited) != 1 {
+		t.Errorf("GetLog limit 1 = %+v, %v", limited, err)
+	}
+
+	root, err := s.GetCommitFiles(commits[1].Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(root) != 1 || root[0].Path != "a.go" || root[0].Status != "A" || root[0].Commit != commits[1].Hash {
+		t.Errorf("root commit files = %+v, want a.go added", root)
+	}
+
+	files, err := s.GetCommitFiles(commits[0].Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Status+" "+f.OldPath+" "+f.Path)
+	}
+	if want := []string{"R a.go b.go", "A  c.go", "A  go.mod"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("commit files = %q, want %q", paths, want)
+	}
+
+	diff, err := s.GetCommitDiff(files[1], DefaultDiffOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Commit != commits[0].Hash || len(diff.Hunks) != 1 {
+		t.Fatalf("commit diff = %+v", diff)
+	}
+	assertLines(t, diff.Hunks[0].Lines, []DiffLine{
+		{Content: "package a", Type: LineAdded, NewLineNum: 1},
+		{Content: "", Type: LineAdded, NewLineNum: 2},
+		{Content: "var c = 1", Type: LineAdded, NewLineNum: 3},
+	})
+}
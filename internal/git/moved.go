@@ -0,0 +1,102 @@
+package git
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minMovedAlnum is the number of alphanumeric characters a block needs
+// before it counts as moved, matching git's --color-moved threshold.
+const minMovedAlnum = 20
+
+type lineRef struct {
+	hunk, line int
+}
+
+// markMoved flags removed lines that reappear as added lines elsewhere in
+// the same file, in the spirit of git diff --color-moved=blocks. Each run
+// of removed lines is matched against the longest run of identical added
+// lines; short runs are ignored so that braces and blank lines do not
+// light up.
+func markMoved(diff *FileDiff, ignoreSpace bool) {
+	normalize := func(s string) string {
+		if ignoreSpace {
+			return strings.Join(strings.Fields(s), "")
+		}
+		return s
+	}
+
+	var removed, added []lineRef
+	for h, hunk := range diff.Hunks {
+		for i, line := range hunk.Lines {
+			switch line.Type {
+			case LineRemoved:
+				removed = append(removed, lineRef{h, i})
+			case LineAdded:
+				added = append(added, lineRef{h, i})
+			}
+		}
+	}
+
+	lineOf := func(ref lineRef) *DiffLine {
+		return &diff.Hunks[ref.hunk].Lines[ref.line]
+	}
+
+	addedAt := make(map[string][]int)
+	for i, ref := range added {
+		key := normalize(lineOf(ref).Content)
+		if key == "" {
+			continue
+		}
+		addedAt[key] = append(addedAt[key], i)
+	}
+
+	// matchLength counts how many lines from removed[r] and added[a] on
+	// match while staying contiguous in both files. Added lines already
+	// claimed by an earlier block end the match, so that two identical
+	// removed blocks do not both point at one destination.
+	matchLength := func(r, a int) int {
+		n := 0
+		for r+n < len(removed) && a+n < len(added) {
+			old, cur := lineOf(removed[r+n]), lineOf(added[a+n])
+			if cur.Moved || old.OldLineNum != lineOf(removed[r]).OldLineNum+n ||
+				cur.NewLineNum != lineOf(added[a]).NewLineNum+n ||
+				normalize(old.Content) != normalize(cur.Content) {
+				break
+			}
+			n++
+		}
+		return n
+	}
+
+	for r := 0; r < len(removed); {
+		best, bestLen := -1, 0
+		for _, a := range addedAt[normalize(lineOf(removed[r]).Content)] {
+			if n := matchLength(r, a); n > bestLen {
+				best, bestLen = a, n
+			}
+		}
+
+		if best < 0 || alnumCount(removed[r:r+bestLen], lineOf) < minMovedAlnum {
+			r++
+			continue
+		}
+		for k := 0; k < bestLen; k++ {
+			lineOf(removed[r+k]).Moved = true
+			lineOf(added[best+k]).Moved = true
+		}
+		r += bestLen
+	}
+}
+
+func alnumCount(refs []lineRef, lineOf func(lineRef) *DiffLine) int {
+	n := 0
+	for _, ref := range refs {
+		for _, c := range lineOf(ref).Content {
+			if unicode.IsLetter(c) || unicode.IsDigit(c) {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,142 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// movedLines parses a diff, marks moved blocks and returns the content of
+// the moved lines, prefixed with - or +.
+func movedLines(t *testing.T, diffText string, ignoreSpace bool) []string {
+	t.Helper()
+	diff, err := (&Service{}).parseDiff("a.go", false, strings.NewReader(diffText), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	markMoved(diff, ignoreSpace)
+	var moved []string
+	for _, h := range diff.Hunks {
+		for _, line := range h.Lines {
+			if !line.Moved {
+				continue
+			}
+			sign := "+"
+			if line.Type == LineRemoved {
+				sign = "-"
+			}
+			moved = append(moved, sign+line.Content)
+		}
+	}
+	return moved
+}
+
+func TestMarkMoved(t *testing.T) {
+	tests := []struct {
+		name        string
+		diff        string
+		ignoreSpace bool
+		want        []string
+	}{
+		{
+			name: "block moved down",
+			diff: `@@ -1,4 +1,2 @@
+ package a
+-func helper() int {
+-	return computeSomething()
+ var x = 1
+@@ -10,1 +8,3 @@
+ var y = 2
++func helper() int {
++	return computeSomething()
+`,
+			want: []string{"-func helper() int {", "-\treturn computeSomething()", "+func helper() int {", "+\treturn computeSomething()"},
+		},
+		{
+			name: "short lines are not moves",
+			diff: `@@ -1,3 +1,3 @@
+-}
+ var x = 1
++}
+`,
+		},
+		{
+			name: "changed lines are not moves",
+			diff: `@@ -1,2 +1,2 @@
+-func helper() int { return computeSomething() }
++func helper() int { return computeSomethingElse() }
+`,
+		},
+		{
+			name: "only the matching part of a block moves",
+			diff: `@@ -1,4 +1,4 @@
+-const first = "moved along with second"
+-const second = "moved along with first"
+-const third = "left behind and deleted"
+ var x = 1
++const first = "moved along with second"
++const second = "moved along with first"
+`,
+			want: []string{
+				`-const first = "moved along with second"`, `-const second = "moved along with first"`,
+				`+const first = "moved along with second"`, `+const second = "moved along with first"`,
+			},
+		},
+		{
+			name: "identical blocks share one destination",
+			diff: `@@ -1,5 +1,1 @@
+-func helper() int {
+-	return computeSomething()
+ var x = 1
+-func helper() int {
+-	return computeSomething()
+@@ -10,1 +8,3 @@
+ var y = 2
++func helper() int {
++	return computeSomething()
+`,
+			want: []string{"-func helper() int {", "-\treturn computeSomething()", "+func helper() int {", "+\treturn computeSomething()"},
+		},
+		{
+			name: "reindented without ignoring whitespace",
+			diff: `@@ -1,3 +1,3 @@
+-return computeSomething(alpha, beta)
+ var x = 1
++	return computeSomething(alpha, beta)
+`,
+		},
+		{
+			name: "reindented ignoring whitespace",
+			diff: `@@ -1,3 +1,3 @@
+-return computeSomething(alpha, beta)
+ var x = 1
++	return computeSomething(alpha, beta)
+`,
+			ignoreSpace: true,
+			want:        []string{"-return computeSomething(alpha, beta)", "+\treturn computeSomething(alpha, beta)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := movedLines(t, tt.diff, tt.ignoreSpace); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("moved = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffOptionsArgs(t *testing.T) {
+	tests := []struct {
+		opts DiffOptions
+		want []string
+	}{
+		{DiffOptions{Context: 3}, []string{"-U3"}},
+		{DiffOptions{Context: -1}, []string{"-U0"}},
+		{DiffOptions{Context: 5, IgnoreWhitespace: true, IgnoreBlankLines: true}, []string{"-U5", "-w", "--ignore-blank-lines"}},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.args(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v: args = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
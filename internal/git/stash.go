@@ -0,0 +1,69 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// stashFormat is logFormat with the stash@{n} ref in place of the short
+// hash.
+const stashFormat = "--format=%H%x00%gd%x00%an%x00%at%x00%s"
+
+// GetStashes returns the stash entries, newest first.
+func (s *Service) GetStashes() ([]Commit, error) {
+	output, err := s.run("stash", "list", "-z", stashFormat)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(string(output)), nil
+}
+
+// GetStashFiles returns the .go, go.mod and go.sum files saved in a stash
+// entry. Tracked changes are relative to the commit the stash was made on;
+// untracked files saved with --include-untracked are returned as
+// unversioned files of the stash's third parent.
+func (s *Service) GetStashFiles(hash string) ([]FileStatus, error) {
+	files, err := s.GetCommitFiles(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.run("rev-parse", "--verify", "-q", hash+"^3")
+	if err != nil {
+		return files, nil
+	}
+	untracked, err := s.GetCommitFiles(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range untracked {
+		file.Unversioned = true
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// ApplyStash applies a stash entry to the working tree, keeping it in the
+// stash list.
+func (s *Service) ApplyStash(ref string) (string, error) {
+	return s.stash("apply", ref)
+}
+
+// PopStash applies a stash entry and drops it if it applied cleanly.
+func (s *Service) PopStash(ref string) (string, error) {
+	return s.stash("pop", ref)
+}
+
+// DropStash removes a stash entry.
+func (s *Service) DropStash(ref string) (string, error) {
+	return s.stash("drop", ref)
+}
+
+// stash runs a git stash subcommand and returns its combined output, which
+// explains conflicts or local changes that stop an apply.
+func (s *Service) stash(op, ref string) (string, error) {
+	cmd := exec.Command("git", "stash", op, ref)
+	cmd.Dir = s.repoPath
+	output, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(output)), err
+}
@@ -0,0 +1,70 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStashes(t *testing.T) {
+	dir, gitCmd := testRepo(t)
+	writeFile(t, dir, "a.go", "package a\n")
+	writeFile(t, dir, "go.mod", "module example.com/a\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "init")
+
+	s := NewService(dir)
+	if stashes, err := s.GetStashes(); err != nil || len(stashes) != 0 {
+		t.Fatalf("no stashes: GetStashes = %+v, %v", stashes, err)
+	}
+
+	writeFile(t, dir, "a.go", "package a\n\nvar a = 1\n")
+	writeFile(t, dir, "new.go", "package a\n")
+	writeFile(t, dir, "notes.txt", "notes\n")
+	writeFile(t, dir, "go.mod", "module example.com/a\n\ngo 1.22\n")
+	writeFile(t, dir, "go.sum", "")
+	gitCmd("stash", "push", "-q", "--include-untracked", "-m", "work in progress")
+
+	stashes, err := s.GetStashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stashes) != 1 || stashes[0].Short != "stash@{0}" || !strings.HasSuffix(stashes[0].Subject, ": work in progress") {
+		t.Fatalf("GetStashes = %+v", stashes)
+	}
+
+	files, err := s.GetStashFiles(stashes[0].Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for _, f := range files {
+		got[f.Path] = f.Unversioned
+	}
+	if len(got) != 4 || got["a.go"] || !got["new.go"] || got["go.mod"] || !got["go.sum"] {
+		t.Errorf("stash files = %+v, want a.go and go.mod tracked, new.go and go.sum untracked", files)
+	}
+
+	if _, err := s.ApplyStash(stashes[0].Short); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.go")); err != nil {
+		t.Errorf("apply did not restore new.go: %v", err)
+	}
+	if stashes, _ := s.GetStashes(); len(stashes) != 1 {
+		t.Errorf("after apply: %d stashes, want 1", len(stashes))
+	}
+
+	// Applying again conflicts with the untracked files now present, and
+	// the output says why.
+	if out, err := s.PopStash("stash@{0}"); err == nil || out == "" {
+		t.Errorf("PopStash over local changes = %q, %v, want an explained error", out, err)
+	}
+	if _, err := s.DropStash("stash@{0}"); err != nil {
+		t.Fatal(err)
+	}
+	if stashes, _ := s.GetStashes(); len(stashes) != 0 {
+		t.Errorf("after drop: %d stashes, want 0", len(stashes))
+	}
+}
@@ -0,0 +1,194 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// statusEntry is one record of git status --porcelain=v2 output.
+type statusEntry struct {
+	// Kind is the record type: '1' ordinary, '2' renamed or copied,
+	// 'u' unmerged, '?' untracked or '!' ignored.
+	Kind byte
+	// X and Y are the index and worktree status, '.' when unchanged.
+	X, Y      byte
+	Submodule string
+	// Modes in HEAD, the index and the worktree. Unmerged entries use
+	// ModeIndex for stage 2 (ours).
+	ModeHead     string
+	ModeIndex    string
+	ModeWorktree string
+	// Score is the rename or copy similarity (0-100).
+	Score    int
+	Path     string
+	OrigPath string
+}
+
+// parseStatusV2 parses git status --porcelain=v2 -z output. Header lines
+// starting with '#' are skipped.
+func parseStatusV2(output []byte) ([]statusEntry, error) {
+	var entries []statusEntry
+	records := bytes.Split(output, []byte{0})
+	for i := 0; i < len(records); i++ {
+		record := string(records[i])
+		if record == "" || record[0] == '#' {
+			continue
+		}
+
+		switch record[0] {
+		case '?', '!':
+			entries = append(entries, statusEntry{
+				Kind: record[0],
+				X:    record[0],
+				Y:    record[0],
+				Path: record[2:],
+			})
+
+		case '1':
+			// 1 XY sub mH mI mW hH hI path
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) != 9 {
+				return nil, fmt.Errorf("malformed status record %q", record)
+			}
+			entries = append(entries, statusEntry{
+				Kind:         '1',
+				X:            fields[1][0],
+				Y:            fields[1][1],
+				Submodule:    fields[2],
+				ModeHead:     fields[3],
+				ModeIndex:    fields[4],
+				ModeWorktree: fields[5],
+				Path:         fields[8],
+			})
+
+		case '2':
+			// 2 XY sub mH mI mW hH hI Xscore path, then the original path
+			// as the next NUL-terminated field.
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) != 10 || i+1 >= len(records) || len(records[i+1]) == 0 {
+				return nil, fmt.Errorf("malformed status record %q", record)
+			}
+			i++
+			entries = append(entries, statusEntry{
+				Kind:         '2',
+				X:            fields[1][0],
+				Y:            fields[1][1],
+				Submodule:    fields[2],
+				ModeHead:     fields[3],
+				ModeIndex:    fields[4],
+				ModeWorktree: fields[5],
+				Score:        parseNum(fields[8][1:]),
+				Path:         fields[9],
+				OrigPath:     string(records[i]),
+			})
+
+		case 'u':
+			// u XY sub m1 m2 m3 mW h1 h2 h3 path
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) != 11 {
+				return nil, fmt.Errorf("malformed status record %q", record)
+			}
+			entries = append(entries, statusEntry{
+				Kind:         'u',
+				X:            fields[1][0],
+				Y:            fields[1][1],
+				Submodule:    fields[2],
+				ModeHead:     fields[3],
+				ModeIndex:    fields[4],
+				ModeWorktree: fields[6],
+				Path:         fields[10],
+			})
+
+		default:
+			return nil, fmt.Errorf("unknown status record %q", record)
+		}
+	}
+	return entries, nil
+}
+
+// files converts a status entry into the file list entries grua shows: one
+// for staged changes and one for unstaged changes, or a single entry for
+// untracked and unmerged paths.
+func (e statusEntry) files() []FileStatus {
+	switch e.Kind {
+	case '!':
+		return nil
+	case '?':
+		return []FileStatus{{
+			Path:        e.Path,
+			Status:      "N",
+			Unversioned: true,
+		}}
+	case 'u':
+		return []FileStatus{{
+			Path:      e.Path,
+			Status:    string([]byte{e.X, e.Y}),
+			Unmerged:  true,
+			Submodule: e.submoduleState(),
+		}}
+	}
+
+	var files []FileStatus
+	if e.X != '.' {
+		f := FileStatus{
+			Path:      e.Path,
+			Status:    string(e.X),
+			Staged:    true,
+			Deleted:   e.X == 'D',
+			Submodule: e.submoduleState(),
+		}
+		if e.Kind == '2' {
+			f.OldPath = e.OrigPath
+			f.Similarity = e.Score
+		}
+		f.OldMode, f.NewMode = modeChange(e.ModeHead, e.ModeIndex)
+		files = append(files, f)
+	}
+	if e.Y != '.' {
+		f := FileStatus{
+			Path:      e.Path,
+			Status:    string(e.Y),
+			Deleted:   e.Y == 'D',
+			Submodule: e.submoduleState(),
+		}
+		f.OldMode, f.NewMode = modeChange(e.ModeIndex, e.ModeWorktree)
+		files = append(files, f)
+	}
+	return files
+}
+
+// modeChange returns the two modes if they differ and both sides exist.
+func modeChange(from, to string) (string, string) {
+	if from == to || isNullMode(from) || isNullMode(to) {
+		return "", ""
+	}
+	return from, to
+}
+
+func isNullMode(mode string) bool {
+	return strings.Trim(mode, "0") == ""
+}
+
+// submoduleState describes the sub field of a status record, which is "N..."
+// for ordinary files and "S<c><m><u>" for submodules.
+func (e statusEntry) submoduleState() string {
+	if len(e.Submodule) != 4 || e.Submodule[0] != 'S' {
+		return ""
+	}
+
+	var parts []string
+	if e.Submodule[1] == 'C' {
+		parts = append(parts, "new commits")
+	}
+	if e.Submodule[2] == 'M' {
+		parts = append(parts, "modified content")
+	}
+	if e.Submodule[3] == 'U' {
+		parts = append(parts, "untracked content")
+	}
+	if len(parts) == 0 {
+		return "submodule"
+	}
+	return "submodule: " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,183 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const hash = "0123456789abcdef0123456789abcdef01234567"
+
+// record joins the fields of a porcelain v2 record, which -z terminates
+// with NUL.
+func record(fields ...string) string {
+	return strings.Join(fields, " ") + "\x00"
+}
+
+func TestParseStatusV2(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []statusEntry
+		files  []FileStatus
+	}{
+		{
+			name:   "headers only",
+			output: "# branch.oid " + hash + "\x00# branch.head main\x00",
+		},
+		{
+			name:   "modified in worktree",
+			output: record("1", ".M", "N...", "100644", "100644", "100644", hash, hash, "main.go"),
+			want: []statusEntry{{
+				Kind: '1', X: '.', Y: 'M', Submodule: "N...",
+				ModeHead: "100644", ModeIndex: "100644", ModeWorktree: "100644",
+				Path: "main.go",
+			}},
+			files: []FileStatus{{Path: "main.go", Status: "M"}},
+		},
+		{
+			name:   "staged and unstaged with mode change",
+			output: record("1", "MM", "N...", "100644", "100755", "100755", hash, hash, "run.sh"),
+			want: []statusEntry{{
+				Kind: '1', X: 'M', Y: 'M', Submodule: "N...",
+				ModeHead: "100644", ModeIndex: "100755", ModeWorktree: "100755",
+				Path: "run.sh",
+			}},
+			files: []FileStatus{
+				{Path: "run.sh", Status: "M", Staged: true, OldMode: "100644", NewMode: "100755"},
+				{Path: "run.sh", Status: "M"},
+			},
+		},
+		{
+			name:   "added and deleted",
+			output: record("1", "A.", "N...", "000000", "100644", "100644", hash, hash, "new.go") + record("1", ".D", "N...", "100644", "100644", "000000", hash, hash, "gone.go"),
+			want: []statusEntry{
+				{Kind: '1', X: 'A', Y: '.', Submodule: "N...", ModeHead: "000000", ModeIndex: "100644", ModeWorktree: "100644", Path: "new.go"},
+				{Kind: '1', X: '.', Y: 'D', Submodule: "N...", ModeHead: "100644", ModeIndex: "100644", ModeWorktree: "000000", Path: "gone.go"},
+			},
+			files: []FileStatus{
+				{Path: "new.go", Status: "A", Staged: true},
+				{Path: "gone.go", Status: "D", Deleted: true},
+			},
+		},
+		{
+			name:   "path with spaces, arrow and unicode",
+			output: record("1", ".M", "N...", "100644", "100644", "100644", hash, hash, "dir with space/a -> b ünï.go"),
+			want: []statusEntry{{
+				Kind: '1', X: '.', Y: 'M', Submodule: "N...",
+				ModeHead: "100644", ModeIndex: "100644", ModeWorktree: "100644",
+				Path: "dir with space/a -> b ünï.go",
+			}},
+			files: []FileStatus{{Path: "dir with space/a -> b ünï.go", Status: "M"}},
+		},
+		{
+			name:   "rename",
+			output: record("2", "R.", "N...", "100644", "100644", "100644", hash, hash, "R87", "new name.go") + "old -> name.go\x00",
+			want: []statusEntry{{
+				Kind: '2', X: 'R', Y: '.', Submodule: "N...",
+				ModeHead: "100644", ModeIndex: "100644", ModeWorktree: "100644",
+				Score: 87, Path: "new name.go", OrigPath: "old -> name.go",
+			}},
+			files: []FileStatus{{Path: "new name.go", Status: "R", Staged: true, OldPath: "old -> name.go", Similarity: 87}},
+		},
+		{
+			name:   "copy modified in worktree",
+			output: record("2", "CM", "N...", "100644", "100644", "100644", hash, hash, "C100", "copy.go") + "orig.go\x00",
+			want: []statusEntry{{
+				Kind: '2', X: 'C', Y: 'M', Submodule: "N...",
+				ModeHead: "100644", ModeIndex: "100644", ModeWorktree: "100644",
+				Score: 100, Path: "copy.go", OrigPath: "orig.go",
+			}},
+			files: []FileStatus{
+				{Path: "copy.go", Status: "C", Staged: true, OldPath: "orig.go", Similarity: 100},
+				{Path: "copy.go", Status: "M"},
+			},
+		},
+		{
+			name:   "unmerged",
+			output: record("u", "UU", "N...", "100644", "100644", "100755", "100755", hash, hash, hash, "conflict.go"),
+			want: []statusEntry{{
+				Kind: 'u', X: 'U', Y: 'U', Submodule: "N...",
+				ModeHead: "100644", ModeIndex: "100644", ModeWorktree: "100755",
+				Path: "conflict.go",
+			}},
+			files: []FileStatus{{Path: "conflict.go", Status: "UU", Unmerged: true}},
+		},
+		{
+			name:   "untracked and ignored",
+			output: "? notes ✓.txt\x00! build/out.bin\x00",
+			want: []statusEntry{
+				{Kind: '?', X: '?', Y: '?', Path: "notes ✓.txt"},
+				{Kind: '!', X: '!', Y: '!', Path: "build/out.bin"},
+			},
+			files: []FileStatus{{Path: "notes ✓.txt", Status: "N", Unversioned: true}},
+		},
+		{
+			name:   "submodule with new commits and untracked content",
+			output: record("1", ".M", "SC.U", "160000", "160000", "160000", hash, hash, "vendor/lib"),
+			want: []statusEntry{{
+				Kind: '1', X: '.', Y: 'M', Submodule: "SC.U",
+				ModeHead: "160000", ModeIndex: "160000", ModeWorktree: "160000",
+				Path: "vendor/lib",
+			}},
+			files: []FileStatus{{Path: "vendor/lib", Status: "M", Submodule: "submodule: new commits, untracked content"}},
+		},
+		{
+			name:   "submodule with modified content",
+			output: record("1", ".M", "S.M.", "160000", "160000", "160000", hash, hash, "sub"),
+			want: []statusEntry{{
+				Kind: '1', X: '.', Y: 'M', Submodule: "S.M.",
+				ModeHead: "160000", ModeIndex: "160000", ModeWorktree: "160000",
+				Path: "sub",
+			}},
+			files: []FileStatus{{Path: "sub", Status: "M", Submodule: "submodule: modified content"}},
+		},
+		{
+			name:   "staged submodule",
+			output: record("1", "M.", "S...", "160000", "160000", "160000", hash, hash, "sub"),
+			want: []statusEntry{{
+				Kind: '1', X: 'M', Y: '.', Submodule: "S...",
+				ModeHead: "160000", ModeIndex: "160000", ModeWorktree: "160000",
+				Path: "sub",
+			}},
+			files: []FileStatus{{Path: "sub", Status: "M", Staged: true, Submodule: "submodule"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := parseStatusV2([]byte(tt.output))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("entries = %+v\nwant %+v", entries, tt.want)
+			}
+			var files []FileStatus
+			for _, e := range entries {
+				files = append(files, e.files()...)
+			}
+			if !reflect.DeepEqual(files, tt.files) {
+				t.Errorf("files = %+v\nwant %+v", files, tt.files)
+			}
+		})
+	}
+}
+
+func TestParseStatusV2Malformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{"short ordinary record", record("1", ".M", "N...", "100644", "main.go")},
+		{"rename without original path", record("2", "R.", "N...", "100644", "100644", "100644", hash, hash, "R100", "new.go")},
+		{"short unmerged record", record("u", "UU", "N...", "100644", "conflict.go")},
+		{"unknown record", "x something\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if entries, err := parseStatusV2([]byte(tt.output)); err == nil {
+				t.Errorf("got %+v, want an error", entries)
+			}
+		})
+	}
+}
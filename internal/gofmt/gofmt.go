@@ -0,0 +1,43 @@
+// Package gofmt checks Go files against the formatting gofmt would give
+// them.
+package gofmt
+
+import (
+	"bytes"
+	"go/format"
+	"os"
+)
+
+// Source returns src formatted as gofmt would, which includes sorting the
+// imports of each block.
+func Source(src []byte) ([]byte, error) {
+	return format.Source(src)
+}
+
+// Check reads a file and returns its formatted content, and whether that
+// differs from what is on disk. Files that do not parse return an error.
+func Check(path string) (formatted []byte, changed bool, err error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	formatted, err = Source(src)
+	if err != nil {
+		return nil, false, err
+	}
+	return formatted, !bytes.Equal(src, formatted), nil
+}
+
+// Apply formats a file in place, keeping its permissions. It reports
+// whether the file changed.
+func Apply(path string) (bool, error) {
+	formatted, changed, err := Check(path)
+	if err != nil || !changed {
+		return false, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, formatted, info.Mode().Perm())
+}
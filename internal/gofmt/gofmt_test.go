@@ -0,0 +1,84 @@
+package gofmt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "formatted",
+			src:  "package p\n\nfunc f() {}\n",
+			want: "package p\n\nfunc f() {}\n",
+		},
+		{
+			name: "spacing",
+			src:  "package p\nfunc f( ) {  return }\n",
+			want: "package p\n\nfunc f() { return }\n",
+		},
+		{
+			name: "import sorting",
+			src:  "package p\n\nimport (\n\t\"os\"\n\t\"golang.org/x/mod/module\"\n\t\"fmt\"\n)\n\nvar _, _, _ = os.Args, module.Version{}, fmt.Sprint\n",
+			want: "package p\n\nimport (\n\t\"fmt\"\n\t\"golang.org/x/mod/module\"\n\t\"os\"\n)\n\nvar _, _, _ = os.Args, module.Version{}, fmt.Sprint\n",
+		},
+		{
+			// Missing imports are not resolved, and unused ones are kept.
+			name: "format only",
+			src:  "package p\n\nimport \"os\"\n\nvar _ = strings.ToUpper\n",
+			want: "package p\n\nimport \"os\"\n\nvar _ = strings.ToUpper\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Source([]byte(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Source =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAndApply(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "p.go")
+	if err := os.WriteFile(path, []byte("package p\nvar x=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	formatted, changed, err := Check(path)
+	if err != nil || !changed || string(formatted) != "package p\n\nvar x = 1\n" {
+		t.Fatalf("Check = %q, %v, %v", formatted, changed, err)
+	}
+	if changed, err := Apply(path); err != nil || !changed {
+		t.Fatalf("Apply = %v, %v", changed, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want it kept", info.Mode().Perm())
+	}
+	if changed, err := Apply(path); err != nil || changed {
+		t.Errorf("second Apply = %v, %v, want no change", changed, err)
+	}
+
+	if err := os.WriteFile(path, []byte("package p\nfunc {\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Check(path); err == nil {
+		t.Error("syntax error: got no error")
+	}
+	if _, _, err := Check(filepath.Join(dir, "missing.go")); err == nil {
+		t.Error("missing file: got no error")
+	}
+}
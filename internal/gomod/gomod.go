@@ -0,0 +1,229 @@
+// Package gomod compares two versions of a go.mod file, and of its go.sum,
+// to show how a change alters the module's dependencies. It also finds the
+// modules that changed files belong to.
+package gomod
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ChangeKind is what a change did to a requirement.
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Removed
+	Upgraded
+	Downgraded
+)
+
+// ModuleChange is a requirement the change added, removed or moved to
+// another version. Old is empty for added modules and New for removed
+// ones.
+type ModuleChange struct {
+	Path     string
+	Old      string
+	New      string
+	Kind     ChangeKind
+	Indirect bool
+}
+
+// Replace is a replace directive. Old.Version is empty when every version
+// is replaced, and New.Version when the replacement is a directory.
+type Replace struct {
+	Old module.Version
+	New module.Version
+}
+
+// String renders the directive as it appears in go.mod.
+func (r Replace) String() string {
+	return fmt.Sprintf("%s => %s", versionString(r.Old), versionString(r.New))
+}
+
+func versionString(v module.Version) string {
+	if v.Version == "" {
+		return v.Path
+	}
+	return v.Path + " " + v.Version
+}
+
+// Diff is the structured difference between two versions of a go.mod
+// file. Version fields are empty where a directive is absent.
+type Diff struct {
+	OldGo        string
+	NewGo        string
+	OldToolchain string
+	NewToolchain string
+	// Modules are sorted by path.
+	Modules         []ModuleChange
+	AddedReplaces   []Replace
+	RemovedReplaces []Replace
+	// UnmatchedSums are module versions the change added to go.sum that
+	// go.mod neither requires nor replaces with, usually left over from
+	// an edit that go mod tidy would undo.
+	UnmatchedSums []module.Version
+}
+
+// Empty reports whether the change made no difference to dependencies.
+func (d *Diff) Empty() bool {
+	return d.OldGo == d.NewGo && d.OldToolchain == d.NewToolchain && len(d.Modules) == 0 &&
+		len(d.AddedReplaces) == 0 && len(d.RemovedReplaces) == 0 && len(d.UnmatchedSums) == 0
+}
+
+// Compare parses both versions of a go.mod file and its go.sum and
+// returns their difference. Any of the versions may be nil for a file
+// that does not exist.
+func Compare(oldMod, newMod, oldSum, newSum []byte) (*Diff, error) {
+	oldFile, err := parse(oldMod)
+	if err != nil {
+		return nil, err
+	}
+	newFile, err := parse(newMod)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Diff{}
+	d.OldGo, d.OldToolchain = goVersions(oldFile)
+	d.NewGo, d.NewToolchain = goVersions(newFile)
+
+	oldReqs := requirements(oldFile)
+	newReqs := requirements(newFile)
+	for path, req := range newReqs {
+		old, ok := oldReqs[path]
+		switch {
+		case !ok:
+			d.Modules = append(d.Modules, ModuleChange{Path: path, New: req.Mod.Version, Kind: Added, Indirect: req.Indirect})
+		case old.Mod.Version != req.Mod.Version:
+			kind := Upgraded
+			if semver.Compare(req.Mod.Version, old.Mod.Version) < 0 {
+				kind = Downgraded
+			}
+			d.Modules = append(d.Modules, ModuleChange{Path: path, Old: old.Mod.Version, New: req.Mod.Version, Kind: kind, Indirect: req.Indirect})
+		}
+	}
+	for path, req := range oldReqs {
+		if _, ok := newReqs[path]; !ok {
+			d.Modules = append(d.Modules, ModuleChange{Path: path, Old: req.Mod.Version, Kind: Removed, Indirect: req.Indirect})
+		}
+	}
+	slices.SortFunc(d.Modules, func(a, b ModuleChange) int { return strings.Compare(a.Path, b.Path) })
+
+	oldReplaces := replaces(oldFile)
+	newReplaces := replaces(newFile)
+	for _, r := range newReplaces {
+		if !slices.Contains(oldReplaces, r) {
+			d.AddedReplaces = append(d.AddedReplaces, r)
+		}
+	}
+	for _, r := range oldReplaces {
+		if !slices.Contains(newReplaces, r) {
+			d.RemovedReplaces = append(d.RemovedReplaces, r)
+		}
+	}
+
+	if newFile != nil && prunesGraph(d.NewGo) {
+		d.UnmatchedSums = unmatchedSums(newReqs, newReplaces, oldSum, newSum)
+	}
+	return d, nil
+}
+
+func parse(data []byte) (*modfile.File, error) {
+	if data == nil {
+		return nil, nil
+	}
+	return modfile.Parse("go.mod", data, nil)
+}
+
+func goVersions(f *modfile.File) (goVersion, toolchain string) {
+	if f == nil {
+		return "", ""
+	}
+	if f.Go != nil {
+		goVersion = f.Go.Version
+	}
+	if f.Toolchain != nil {
+		toolchain = f.Toolchain.Name
+	}
+	return goVersion, toolchain
+}
+
+func requirements(f *modfile.File) map[string]*modfile.Require {
+	reqs := make(map[string]*modfile.Require)
+	if f != nil {
+		for _, req := range f.Require {
+			reqs[req.Mod.Path] = req
+		}
+	}
+	return reqs
+}
+
+func replaces(f *modfile.File) []Replace {
+	if f == nil {
+		return nil
+	}
+	var rs []Replace
+	for _, r := range f.Replace {
+		rs = append(rs, Replace{Old: r.Old, New: r.New})
+	}
+	return rs
+}
+
+// prunesGraph reports whether a module at go version v lists every module
+// it builds with, from Go 1.17, so go.sum should hold nothing else.
+func prunesGraph(v string) bool {
+	return v != "" && semver.Compare("v"+v, "v1.17") >= 0
+}
+
+// unmatchedSums returns the module versions with content hashes in newSum
+// but not oldSum that no requirement or replacement accounts for. Hashes
+// of go.mod files alone are needed for the whole module graph, so are not
+// counted.
+func unmatchedSums(reqs map[string]*modfile.Require, rs []Replace, oldSum, newSum []byte) []module.Version {
+	wanted := make(map[module.Version]bool)
+	for _, req := range reqs {
+		wanted[req.Mod] = true
+	}
+	replacedPaths := make(map[string]bool)
+	for _, r := range rs {
+		wanted[r.New] = true
+		replacedPaths[r.New.Path] = true
+	}
+
+	old := make(map[module.Version]bool)
+	for _, v := range sumEntries(oldSum) {
+		old[v] = true
+	}
+	var unmatched []module.Version
+	for _, v := range sumEntries(newSum) {
+		if !old[v] && !wanted[v] && !replacedPaths[v.Path] {
+			unmatched = append(unmatched, v)
+		}
+	}
+	return unmatched
+}
+
+// sumEntries returns the module versions with content hashes in a go.sum
+// file, in order and once each.
+func sumEntries(data []byte) []module.Version {
+	var versions []module.Version
+	seen := make(map[module.Version]bool)
+	for line := range strings.Lines(string(data)) {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		v := module.Version{Path: fields[0], Version: fields[1]}
+		if !seen[v] {
+			seen[v] = true
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
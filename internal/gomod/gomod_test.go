@@ -0,0 +1,155 @@
+package gomod
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/mod/module"
+)
+
+func TestCompare(t *testing.T) {
+	oldMod := []byte(`module example.com/app
+
+go 1.21
+
+require (
+	example.com/kept v1.0.0
+	example.com/up v1.2.0
+	example.com/down v1.4.0
+	example.com/gone v0.1.0 // indirect
+)
+
+replace example.com/old => ../old
+`)
+	newMod := []byte(`module example.com/app
+
+go 1.22
+
+toolchain go1.22.3
+
+require (
+	example.com/kept v1.0.0
+	example.com/up v1.10.0
+	example.com/down v1.3.9
+	example.com/new v0.3.0 // indirect
+)
+
+replace example.com/fork v1.0.0 => example.com/myfork v1.0.1
+`)
+	oldSum := []byte(`example.com/kept v1.0.0 h1:aaa=
+example.com/kept v1.0.0/go.mod h1:bbb=
+`)
+	newSum := []byte(`example.com/kept v1.0.0 h1:aaa=
+example.com/kept v1.0.0/go.mod h1:bbb=
+example.com/new v0.3.0 h1:ccc=
+example.com/myfork v1.0.1 h1:ddd=
+example.com/stray v0.0.1 h1:eee=
+example.com/stray v0.0.1/go.mod h1:fff=
+example.com/graph v1.0.0/go.mod h1:ggg=
+`)
+
+	d, err := Compare(oldMod, newMod, oldSum, newSum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Diff{
+		OldGo:        "1.21",
+		NewGo:        "1.22",
+		NewToolchain: "go1.22.3",
+		Modules: []ModuleChange{
+			{Path: "example.com/down", Old: "v1.4.0", New: "v1.3.9", Kind: Downgraded},
+			{Path: "example.com/gone", Old: "v0.1.0", Kind: Removed, Indirect: true},
+			{Path: "example.com/new", New: "v0.3.0", Kind: Added, Indirect: true},
+			{Path: "example.com/up", Old: "v1.2.0", New: "v1.10.0", Kind: Upgraded},
+		},
+		AddedReplaces: []Replace{{
+			Old: module.Version{Path: "example.com/fork", Version: "v1.0.0"},
+			New: module.Version{Path: "example.com/myfork", Version: "v1.0.1"},
+		}},
+		RemovedReplaces: []Replace{{
+			Old: module.Version{Path: "example.com/old"},
+			New: module.Version{Path: "../old"},
+		}},
+		UnmatchedSums: []module.Version{{Path: "example.com/stray", Version: "v0.0.1"}},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("Compare =\n%+v\nwant\n%+v", d, want)
+	}
+	if d.Empty() {
+		t.Error("diff is empty")
+	}
+	if got := d.AddedReplaces[0].String(); got != "example.com/fork v1.0.0 => example.com/myfork v1.0.1" {
+		t.Errorf("replace = %q", got)
+	}
+	if got := d.RemovedReplaces[0].String(); got != "example.com/old => ../old" {
+		t.Errorf("replace = %q", got)
+	}
+}
+
+func TestCompareMissingFiles(t *testing.T) {
+	mod := []byte("module example.com/app\n\ngo 1.22\n\nrequire example.com/dep v1.0.0\n")
+
+	added, err := Compare(nil, mod, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.OldGo != "" || added.NewGo != "1.22" || len(added.Modules) != 1 || added.Modules[0].Kind != Added {
+		t.Errorf("new go.mod = %+v", added)
+	}
+
+	removed, err := Compare(mod, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed.NewGo != "" || len(removed.Modules) != 1 || removed.Modules[0].Kind != Removed {
+		t.Errorf("deleted go.mod = %+v", removed)
+	}
+
+	same, err := Compare(mod, mod, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !same.Empty() {
+		t.Errorf("unchanged go.mod = %+v, want empty", same)
+	}
+
+	if _, err := Compare(mod, []byte("module\nrequire (\n"), nil, nil); err == nil {
+		t.Error("invalid go.mod: got no error")
+	}
+}
+
+func TestCompareUnprunedModule(t *testing.T) {
+	// Before Go 1.17, go.sum lists the whole module graph, so extra
+	// entries are expected.
+	mod := []byte("module example.com/app\n\ngo 1.16\n")
+	sum := []byte("example.com/transitive v1.0.0 h1:aaa=\n")
+	d, err := Compare(mod, mod, nil, sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.UnmatchedSums) != 0 {
+		t.Errorf("UnmatchedSums = %v, want none", d.UnmatchedSums)
+	}
+}
+
+func TestSumEntries(t *testing.T) {
+	data := []byte(`example.com/a v1.0.0 h1:aaa=
+example.com/a v1.0.0/go.mod h1:bbb=
+example.com/b v0.1.0/go.mod h1:ccc=
+
+malformed line
+example.com/c v2.0.0+incompatible h1:ddd=
+example.com/a v1.0.0 h1:aaa=
+example.com/a v1.1.0 h1:eee=`)
+	want := []module.Version{
+		{Path: "example.com/a", Version: "v1.0.0"},
+		{Path: "example.com/c", Version: "v2.0.0+incompatible"},
+		{Path: "example.com/a", Version: "v1.1.0"},
+	}
+	if got := sumEntries(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("sumEntries = %v, want %v", got, want)
+	}
+	if got := sumEntries(nil); got != nil {
+		t.Errorf("sumEntries(nil) = %v, want nil", got)
+	}
+}
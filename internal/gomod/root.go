@@ -0,0 +1,73 @@
+package gomod
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Roots groups files, given relative to dir, by the root directory of the
+// module containing each, so that go commands can run from there in
+// repositories holding a module below their root, or several modules.
+// Files in no module are left out. A file need not exist: the nearest
+// directory above it that does is used.
+func Roots(dir string, files []string) (map[string][]string, error) {
+	roots := make(map[string][]string)
+	known := make(map[string]string)
+	for _, file := range files {
+		fileDir, err := existingDir(filepath.Join(dir, filepath.Dir(file)))
+		if err != nil {
+			return nil, err
+		}
+		root, ok := known[fileDir]
+		if !ok {
+			if root, err = moduleRoot(fileDir); err != nil {
+				return nil, err
+			}
+			known[fileDir] = root
+		}
+		if root != "" {
+			roots[root] = append(roots[root], file)
+		}
+	}
+	return roots, nil
+}
+
+// moduleRoot asks the go command for the go.mod that applies in dir, and
+// returns its directory, or "" outside any module.
+func moduleRoot(dir string) (string, error) {
+	cmd := exec.Command("go", "env", "GOMOD")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go env GOMOD in %s: %w", dir, err)
+	}
+	gomod := strings.TrimSpace(string(output))
+	if gomod == "" || gomod == os.DevNull {
+		return "", nil
+	}
+	return filepath.Dir(gomod), nil
+}
+
+// existingDir returns dir, or its nearest parent if it was deleted.
+func existingDir(dir string) (string, error) {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil && info.IsDir() {
+			return dir, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no directory above %s", dir)
+		}
+		dir = parent
+	}
+}
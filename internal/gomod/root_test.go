@@ -0,0 +1,43 @@
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRoots(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":       "module example.com/top\n",
+		"a/a.go":       "package a\n",
+		"tools/go.mod": "module example.com/tools\n",
+		"tools/t/t.go": "package t\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Roots(dir, []string{"a/a.go", "tools/t/t.go", "tools/gone/x.go", "gone/y.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		dir:                         {"a/a.go", "gone/y.go"},
+		filepath.Join(dir, "tools"): {"tools/t/t.go", "tools/gone/x.go"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Roots = %v, want %v", got, want)
+	}
+
+	outside := t.TempDir()
+	if got, err := Roots(outside, []string{"x.go"}); err != nil || len(got) != 0 {
+		t.Errorf("outside a module: Roots = %v, %v, want none", got, err)
+	}
+}
@@ -0,0 +1,164 @@
+// Package gotest finds the packages a change can break and runs their
+// tests.
+package gotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"grua/internal/gomod"
+)
+
+// Affected is the set of packages whose tests a change can break.
+type Affected struct {
+	// Packages are the import paths of the packages containing the
+	// changed files and of the module packages that import them, directly
+	// or indirectly, including from tests.
+	Packages []string
+	// Files maps each changed file to the import path of its package.
+	Files map[string]string
+	// Modules splits Packages by the module they belong to.
+	Modules []Module
+}
+
+// Module is the part of a change's affected packages in one module, whose
+// tests run from its root directory Dir.
+type Module struct {
+	Dir      string
+	Packages []string
+}
+
+// listedPackage is the part of go list's output used to find reverse
+// dependencies.
+type listedPackage struct {
+	ImportPath   string
+	Dir          string
+	Imports      []string
+	TestImports  []string
+	XTestImports []string
+}
+
+// FindAffected lists the packages of the modules containing files, given
+// relative to dir, and returns those affected by changes to the files. A
+// deleted file affects the package left in its directory.
+func FindAffected(dir string, files []string) (*Affected, error) {
+	roots, err := gomod.Roots(dir, files)
+	if err != nil {
+		return nil, err
+	}
+	affected := &Affected{Files: make(map[string]string)}
+	for _, root := range slices.Sorted(maps.Keys(roots)) {
+		pkgs, err := findAffected(dir, root, roots[root], affected.Files)
+		if err != nil {
+			return nil, err
+		}
+		if len(pkgs) > 0 {
+			affected.Modules = append(affected.Modules, Module{Dir: root, Packages: pkgs})
+			affected.Packages = append(affected.Packages, pkgs...)
+		}
+	}
+	slices.Sort(affected.Packages)
+	return affected, nil
+}
+
+// findAffected returns the packages of the module at root affected by
+// changes to files, and records the package of each file in pkgOf.
+func findAffected(dir, root string, files []string, pkgOf map[string]string) ([]string, error) {
+	pkgs, err := listPackages(root)
+	if err != nil {
+		return nil, err
+	}
+
+	byDir := make(map[string]string, len(pkgs))
+	importers := make(map[string][]string)
+	for _, pkg := range pkgs {
+		byDir[pkg.Dir] = pkg.ImportPath
+		deps := slices.Concat(pkg.Imports, pkg.TestImports, pkg.XTestImports)
+		for _, dep := range deps {
+			importers[dep] = append(importers[dep], pkg.ImportPath)
+		}
+	}
+
+	var affected []string
+	seen := make(map[string]bool)
+	var queue []string
+	for _, file := range files {
+		pkg, ok := byDir[filepath.Join(dir, filepath.Dir(file))]
+		if !ok {
+			continue
+		}
+		pkgOf[file] = pkg
+		if !seen[pkg] {
+			seen[pkg] = true
+			queue = append(queue, pkg)
+		}
+	}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		affected = append(affected, pkg)
+		for _, importer := range importers[pkg] {
+			if !seen[importer] {
+				seen[importer] = true
+				queue = append(queue, importer)
+			}
+		}
+	}
+	slices.Sort(affected)
+	return affected, nil
+}
+
+// listPackages runs go list over the module rooted at dir. Packages with errors
+// are listed too, since a change that breaks the build should still have
+// its tests run and fail.
+func listPackages(dir string) ([]listedPackage, error) {
+	cmd := exec.Command("go", "list", "-e",
+		"-json=ImportPath,Dir,Imports,TestImports,XTestImports", "./...")
+	cmd.Dir = dir
+	cmd.Env = goEnv()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %s", firstLine(stderr.String(), err))
+	}
+
+	var pkgs []listedPackage
+	dec := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var pkg listedPackage
+		if err := dec.Decode(&pkg); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// goEnv is the environment for go commands: offline, and never updating
+// go.mod.
+func goEnv() []string {
+	return append(os.Environ(), "GOPROXY=off", "GOFLAGS=")
+}
+
+// firstLine returns the first line of a command's error output, or err if
+// there was none.
+func firstLine(output string, err error) string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return err.Error()
+	}
+	line, _, _ := strings.Cut(output, "\n")
+	return line
+}
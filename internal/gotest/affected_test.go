@@ -0,0 +1,85 @@
+package gotest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeModule writes files into a new module in a temporary directory and
+// returns its path.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/m\n\ngo 1.22\n"
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFindAffected(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go":      "package a\n\nfunc A() int { return 1 }\n",
+		"b/b.go":      "package b\n\nimport \"example.com/m/a\"\n\nvar B = a.A()\n",
+		"c/c.go":      "package c\n",
+		"c/c_test.go": "package c\n\nimport _ \"example.com/m/b\"\n",
+		"d/d.go":      "package d\n",
+		"e/e.go":      "package e\n",
+		"e/x_test.go": "package e_test\n\nimport _ \"example.com/m/c\"\n",
+	})
+
+	affected, err := FindAffected(dir, []string{"a/a.go", "d/gone.go", "README.md", "nowhere/x.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// c only imports b from its tests, and e from its external tests.
+	wantPkgs := []string{"example.com/m/a", "example.com/m/b", "example.com/m/c", "example.com/m/d", "example.com/m/e"}
+	if !reflect.DeepEqual(affected.Packages, wantPkgs) {
+		t.Errorf("Packages = %q, want %q", affected.Packages, wantPkgs)
+	}
+	wantFiles := map[string]string{"a/a.go": "example.com/m/a", "d/gone.go": "example.com/m/d"}
+	if !reflect.DeepEqual(affected.Files, wantFiles) {
+		t.Errorf("Files = %v, want %v", affected.Files, wantFiles)
+	}
+
+	unrelated, err := FindAffected(dir, []string{"d/d.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unrelated.Packages, []string{"example.com/m/d"}) {
+		t.Errorf("Packages = %q, want d only", unrelated.Packages)
+	}
+}
+
+func TestFindAffectedModules(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go":       "package a\n",
+		"tools/go.mod": "module example.com/tools\n\ngo 1.22\n",
+		"tools/t/t.go": "package t\n\nimport _ \"example.com/tools/u\"\n",
+		"tools/u/u.go": "package u\n",
+	})
+
+	affected, err := FindAffected(dir, []string{"a/a.go", "tools/u/u.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Module{
+		{Dir: dir, Packages: []string{"example.com/m/a"}},
+		{Dir: filepath.Join(dir, "tools"), Packages: []string{"example.com/tools/t", "example.com/tools/u"}},
+	}
+	if !reflect.DeepEqual(affected.Modules, want) {
+		t.Errorf("Modules = %+v, want %+v", affected.Modules, want)
+	}
+	wantPkgs := []string{"example.com/m/a", "example.com/tools/t", "example.com/tools/u"}
+	if !reflect.DeepEqual(affected.Packages, wantPkgs) {
+		t.Errorf("Packages = %q, want %q", affected.Packages, wantPkgs)
+	}
+}
@@ -0,0 +1,73 @@
+package gotest
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/tools/cover"
+)
+
+// Cover asks Run to write a coverage profile to Profile, counting the
+// statements of Packages whichever package's tests run them.
+type Cover struct {
+	Profile  string
+	Packages []string
+}
+
+// Coverage maps the lines of a file that hold statements to whether any
+// of those statements ran. Lines without statements are absent.
+type Coverage map[int]bool
+
+// ReadCoverage reads the profile written by Run and returns the coverage
+// of files, which maps paths relative to dir to their import paths as in
+// Affected.Files.
+func ReadCoverage(profile, dir string, files map[string]string) (map[string]Coverage, error) {
+	profiles, err := cover.ParseProfiles(profile)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*cover.Profile, len(profiles))
+	for _, p := range profiles {
+		byName[p.FileName] = p
+	}
+
+	coverage := make(map[string]Coverage)
+	for file, pkg := range files {
+		p, ok := byName[path.Join(pkg, path.Base(filepath.ToSlash(file)))]
+		if !ok {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			continue
+		}
+		coverage[file] = lineCoverage(p, bytes.Split(src, []byte("\n")))
+	}
+	return coverage, nil
+}
+
+// lineCoverage spreads the blocks of a profile over the lines of src. A
+// block only counts on lines where it covers more than braces, so a
+// function's signature is not taken for a statement of its body.
+func lineCoverage(p *cover.Profile, lines [][]byte) Coverage {
+	coverage := make(Coverage)
+	for _, b := range p.Blocks {
+		for n := b.StartLine; n <= b.EndLine && n <= len(lines); n++ {
+			line := lines[n-1]
+			from, to := 0, len(line)
+			if n == b.StartLine {
+				from = min(b.StartCol-1, len(line))
+			}
+			if n == b.EndLine {
+				to = min(max(b.EndCol-1, from), len(line))
+			}
+			if len(bytes.Trim(line[from:to], " \t\r{}")) == 0 {
+				continue
+			}
+			coverage[n] = coverage[n] || b.Count > 0
+		}
+	}
+	return coverage
+}
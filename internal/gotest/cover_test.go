@@ -0,0 +1,92 @@
+package gotest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestLineCoverage(t *testing.T) {
+	src := []byte(`package a
+
+func F(n int) int {
+	if n > 0 {
+		return n
+	}
+	return -n
+}
+
+func G() { h(); h() }
+`)
+	p := &cover.Profile{Blocks: []cover.ProfileBlock{
+		// The body of F up to the if, which starts after the signature's
+		// brace.
+		{StartLine: 3, StartCol: 19, EndLine: 4, EndCol: 11, Count: 1},
+		{StartLine: 4, StartCol: 11, EndLine: 6, EndCol: 3, Count: 0},
+		{StartLine: 7, StartCol: 2, EndLine: 7, EndCol: 11, Count: 1},
+		// Two blocks on one line: covered if either ran.
+		{StartLine: 10, StartCol: 10, EndLine: 10, EndCol: 14, Count: 0},
+		{StartLine: 10, StartCol: 16, EndLine: 10, EndCol: 21, Count: 3},
+		// A block past the end of a file that has since shrunk.
+		{StartLine: 40, StartCol: 1, EndLine: 41, EndCol: 2, Count: 1},
+	}}
+	want := Coverage{4: true, 5: false, 7: true, 10: true}
+	if got := lineCoverage(p, bytes.Split(src, []byte("\n"))); !reflect.DeepEqual(got, want) {
+		t.Errorf("lineCoverage = %v, want %v", got, want)
+	}
+}
+
+func TestReadCoverage(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"a/a.go": `package a
+
+func Abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+`,
+		"a/a_test.go": `package a
+
+import "testing"
+
+func TestAbs(t *testing.T) {
+	if Abs(2) != 2 {
+		t.Fail()
+	}
+}
+`,
+		"b/b.go": "package b\n\nfunc B() int { return 1 }\n",
+	})
+
+	profile := filepath.Join(t.TempDir(), "cover.out")
+	cov := &Cover{Profile: profile, Packages: []string{"example.com/m/a", "example.com/m/b"}}
+	if err := Run(context.Background(), dir, []string{"./a"}, cov, func(Event) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"a/a.go": "example.com/m/a",
+		"b/b.go": "example.com/m/b",
+		"c/c.go": "example.com/m/c",
+	}
+	got, err := ReadCoverage(profile, dir, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// b is not linked into a's tests, so the profile has nothing on it.
+	want := map[string]Coverage{"a/a.go": {4: true, 5: false, 7: true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadCoverage = %v, want %v", got, want)
+	}
+
+	if _, err := ReadCoverage(filepath.Join(dir, "missing.out"), dir, files); !os.IsNotExist(err) {
+		t.Errorf("missing profile: err = %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package gotest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Event is a test2json event from go test -json.
+type Event struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// Run runs go test for pkgs in dir and calls emit for each event as it
+// arrives. Output that is not JSON, such as build errors, is passed on as
+// output events without a package. Failing tests are not an error. If
+// cov is not nil, a coverage profile is written too.
+func Run(ctx context.Context, dir string, pkgs []string, cov *Cover, emit func(Event)) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	args := []string{"test", "-json"}
+	if cov != nil {
+		args = append(args, "-coverprofile="+cov.Profile, "-coverpkg="+strings.Join(cov.Packages, ","))
+	}
+	cmd := exec.CommandContext(ctx, "go", append(args, pkgs...)...)
+	cmd.Dir = dir
+	cmd.Env = goEnv()
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		done <- err
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var event Event
+		if len(line) == 0 || line[0] != '{' || json.Unmarshal(line, &event) != nil {
+			event = Event{Action: "output", Output: string(line) + "\n"}
+		}
+		emit(event)
+	}
+	// Drain the pipe so go test is never blocked writing to it.
+	_, _ = io.Copy(io.Discard, pr)
+
+	err := <-done
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package gotest
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"ok/ok_test.go":    "package ok\n\nimport \"testing\"\n\nfunc TestOK(t *testing.T) {}\n",
+		"bad/bad_test.go":  "package bad\n\nimport \"testing\"\n\nfunc TestBad(t *testing.T) { t.Log(\"why\"); t.Fail() }\n",
+		"broken/broken.go": "package broken\n\nvar x int = \"s\"\n",
+		"broken/b_test.go": "package broken\n",
+		"empty/empty.go":   "package empty\n",
+	})
+
+	results := make(map[string]string)
+	var sawOutput bool
+	pkgs := []string{"./ok", "./bad", "./broken", "./empty"}
+	err := Run(context.Background(), dir, pkgs, nil, func(e Event) {
+		switch e.Action {
+		case "pass", "fail", "skip":
+			results[e.Package+" "+e.Test] = e.Action
+		case "output":
+			if e.Test == "TestBad" && e.Output == "    bad_test.go:5: why\n" {
+				sawOutput = true
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("failing tests returned %v", err)
+	}
+	want := map[string]string{
+		"example.com/m/ok TestOK":   "pass",
+		"example.com/m/ok ":         "pass",
+		"example.com/m/bad TestBad": "fail",
+		"example.com/m/bad ":        "fail",
+		"example.com/m/broken ":     "fail",
+		"example.com/m/empty ":      "skip",
+	}
+	for id, action := range want {
+		if results[id] != action {
+			t.Errorf("%q: %q, want %q", id, results[id], action)
+		}
+	}
+	if !sawOutput {
+		t.Error("TestBad's log output was not reported")
+	}
+
+	if err := Run(context.Background(), dir, nil, nil, nil); err != nil {
+		t.Errorf("no packages: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Run(ctx, dir, pkgs, nil, func(Event) {}); err == nil {
+		t.Error("cancelled run: got no error")
+	}
+}
@@ -15,22 +15,27 @@ const (
 	LineContext LineType = iota
 	LineAdded
 	LineRemoved
+	LineMovedAdded
+	LineMovedRemoved
 )
 
 var (
-	AddedBg       = lipgloss.Color("#1B4B1B")
-	RemovedBg     = lipgloss.Color("#4B1818")
-	AddedFg       = lipgloss.Color("#69FF94")
-	RemovedFg     = lipgloss.Color("#FF6B6B")
-	ColorKeyword  = lipgloss.Color("#FF79C6")
-	ColorString   = lipgloss.Color("#F1FA8C")
-	ColorComment  = lipgloss.Color("#6272A4")
-	ColorFunction = lipgloss.Color("#50FA7B")
-	ColorType     = lipgloss.Color("#8BE9FD")
-	ColorNumber   = lipgloss.Color("#BD93F9")
-	ColorOperator = lipgloss.Color("#FF79C6")
-	ColorDefault  = lipgloss.Color("#F8F8F2")
-	ColorHunk     = lipgloss.Color("#00D7FF")
+	AddedBg        = lipgloss.Color("#1B4B1B")
+	RemovedBg      = lipgloss.Color("#4B1818")
+	AddedFg        = lipgloss.Color("#69FF94")
+	RemovedFg      = lipgloss.Color("#FF6B6B")
+	MovedAddedBg   = lipgloss.Color("#1B3B4B")
+	MovedRemovedBg = lipgloss.Color("#3B1B4B")
+	MovedFg        = lipgloss.Color("#8BE9FD")
+	ColorKeyword   = lipgloss.Color("#FF79C6")
+	ColorString    = lipgloss.Color("#F1FA8C")
+	ColorComment   = lipgloss.Color("#6272A4")
+	ColorFunction  = lipgloss.Color("#50FA7B")
+	ColorType      = lipgloss.Color("#8BE9FD")
+	ColorNumber    = lipgloss.Color("#BD93F9")
+	ColorOperator  = lipgloss.Color("#FF79C6")
+	ColorDefault   = lipgloss.Color("#F8F8F2")
+	ColorHunk      = lipgloss.Color("#00D7FF")
 )
 
 // Highlighter provides Go syntax highlighting with diff support.
@@ -98,12 +103,8 @@ func (h *Highlighter) HighlightLine(line string, lineType LineType, width int) s
 	for _, token := range tokens {
 		color := h.tokenColor(token.Type)
 		style := lipgloss.NewStyle().Foreground(color)
-
-		switch lineType {
-		case LineAdded:
-			style = style.Background(AddedBg)
-		case LineRemoved:
-			style = style.Background(RemovedBg)
+		if bg, ok := Background(lineType); ok {
+			style = style.Background(bg)
 		}
 
 		result.WriteString(style.Render(token.Value))
@@ -115,14 +116,9 @@ func (h *Highlighter) HighlightLine(line string, lineType LineType, width int) s
 		visibleLen := visibleLength(line)
 		if visibleLen < width {
 			padding := strings.Repeat(" ", width-visibleLen)
-			var bgStyle lipgloss.Style
-			switch lineType {
-			case LineAdded:
-				bgStyle = lipgloss.NewStyle().Background(AddedBg)
-			case LineRemoved:
-				bgStyle = lipgloss.NewStyle().Background(RemovedBg)
-			default:
-				bgStyle = lipgloss.NewStyle()
+			bgStyle := lipgloss.NewStyle()
+			if bg, ok := Background(lineType); ok {
+				bgStyle = bgStyle.Background(bg)
 			}
 			rendered += bgStyle.Render(padding)
 		}
@@ -138,6 +134,10 @@ func (h *Highlighter) applyBackground(text string, lineType LineType, width int)
 		style = lipgloss.NewStyle().Background(AddedBg).Foreground(AddedFg)
 	case LineRemoved:
 		style = lipgloss.NewStyle().Background(RemovedBg).Foreground(RemovedFg)
+	case LineMovedAdded:
+		style = lipgloss.NewStyle().Background(MovedAddedBg).Foreground(MovedFg)
+	case LineMovedRemoved:
+		style = lipgloss.NewStyle().Background(MovedRemovedBg).Foreground(MovedFg)
 	default:
 		style = lipgloss.NewStyle()
 	}
@@ -149,6 +149,22 @@ func (h *Highlighter) applyBackground(text string, lineType LineType, width int)
 	return style.Render(text)
 }
 
+// Background returns the diff background for a line type, if it has one.
+func Background(lineType LineType) (lipgloss.Color, bool) {
+	switch lineType {
+	case LineAdded:
+		return AddedBg, true
+	case LineRemoved:
+		return RemovedBg, true
+	case LineMovedAdded:
+		return MovedAddedBg, true
+	case LineMovedRemoved:
+		return MovedRemovedBg, true
+	default:
+		return "", false
+	}
+}
+
 func visibleLength(s string) int {
 	count := 0
 	inEscape := false
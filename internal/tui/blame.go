@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"grua/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type blameMsg struct {
+	key   fileKey
+	blame *git.Blame
+	err   error
+}
+
+// blameInfoMsg carries the full message of a blamed commit for the popup.
+type blameInfoMsg struct {
+	commit  git.BlameCommit
+	message string
+	err     error
+}
+
+func (m *Model) loadBlame(file git.FileStatus) tea.Cmd {
+	key := keyOf(file)
+	return func() tea.Msg {
+		blame, err := m.gitService.GetBlame(file)
+		return blameMsg{key: key, blame: blame, err: err}
+	}
+}
+
+// ensureBlame loads the blame for the current file if the gutter is on
+// and it has not been loaded yet. The periodic diff reload does not
+// trigger another load.
+func (m *Model) ensureBlame() tea.Cmd {
+	if !m.showBlame || m.currentFile == nil || m.currentFile.Unmerged {
+		return nil
+	}
+	key := keyOf(*m.currentFile)
+	if key == m.blameFor {
+		return nil
+	}
+	m.blameFor = key
+	m.diffView.SetBlame(nil)
+	return m.loadBlame(*m.currentFile)
+}
+
+// toggleBlame shows or hides the blame gutter.
+func (m *Model) toggleBlame() tea.Cmd {
+	m.showBlame = !m.showBlame
+	if !m.showBlame {
+		m.blameFor = fileKey{}
+		m.diffView.SetBlame(nil)
+		return nil
+	}
+	return m.ensureBlame()
+}
+
+// showBlameInfo opens the popup for the commit behind the selected line.
+func (m *Model) showBlameInfo() tea.Cmd {
+	if !m.showBlame {
+		m.statusMsg = "Press " + m.keys.Blame.Help().Key + " to show blame first"
+		return nil
+	}
+	commit := m.diffView.SelectedBlame()
+	if commit == nil {
+		m.statusMsg = "No blame for this line"
+		return nil
+	}
+	if !commit.Committed() {
+		m.statusMsg = "Line is staged but not committed"
+		return nil
+	}
+	c := *commit
+	return func() tea.Msg {
+		message, err := m.gitService.CommitMessage(c.Hash)
+		return blameInfoMsg{commit: c, message: message, err: err}
+	}
+}
+
+// renderBlameInfo draws the commit popup over the main view.
+func (m *Model) renderBlameInfo() string {
+	info := m.blameInfo
+	width := min(m.width-4, 80)
+	innerWidth := width - 4
+
+	header := []string{
+		m.styles.CommitHash.Render("commit " + info.commit.Hash),
+		m.styles.BlameAuthor.Render("Author: " + info.commit.Author),
+		m.styles.CommitMeta.Render("Date:   " + info.commit.Time.Format("Mon Jan 2 15:04:05 2006 -0700")),
+		"",
+	}
+
+	var body []string
+	for _, line := range strings.Split(info.message, "\n") {
+		body = append(body, truncate(expandTabs(line), innerWidth))
+	}
+	maxBody := max(m.height-len(header)-8, 1)
+	if len(body) > maxBody {
+		body = append(body[:maxBody-1], m.styles.CommitMeta.Render(
+			fmt.Sprintf("… %d more lines", len(body)-maxBody+1)))
+	}
+
+	footer := []string{"", m.styles.CommitMeta.Italic(true).Render("Press any key to close")}
+	box := m.styles.FileListBorderActive.
+		Width(width).
+		Render(strings.Join(append(append(header, body...), footer...), "\n"))
+
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"grua/internal/checks"
+	"grua/internal/complexity"
+	"grua/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// checksMsg carries the findings of a checks run on the lines added by
+// the change.
+type checksMsg struct {
+	signature  string
+	findings   []checks.Finding
+	complexity map[string][]complexity.Delta
+	err        error
+}
+
+// checkSignature identifies the state of the changed files, so checks run
+// again only after something changed.
+func (m *Model) checkSignature(files []git.FileStatus) string {
+	var b strings.Builder
+	for _, file := range files {
+		fmt.Fprintf(&b, "%s %s %t %t", file.Path, file.Status, file.Staged, file.Unversioned)
+		if info, err := os.Stat(filepath.Join(m.repoPath, file.Path)); err == nil {
+			fmt.Fprintf(&b, " %d %d", info.Size(), info.ModTime().UnixNano())
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// maybeRunChecks starts a checks run in the background if the changed
+// files differ from the last run and none is in progress.
+func (m *Model) maybeRunChecks(files []git.FileStatus) tea.Cmd {
+	signature := m.checkSignature(files)
+	if m.checking || signature == m.lastCheck {
+		return nil
+	}
+	m.checking = true
+	m.lastCheck = signature
+
+	return func() tea.Msg {
+		changes, err := checks.CheckChanges(m.gitService, m.repoPath, files, m.cfg.Rules)
+		if err != nil {
+			return checksMsg{signature: signature, err: err}
+		}
+		deltas := make(map[string][]complexity.Delta)
+		for _, file := range checks.Targets(files) {
+			if !file.Deleted && strings.HasSuffix(file.Path, ".go") {
+				deltas[file.Path] = m.fileComplexity(file, changes.Added[file.Path])
+			}
+		}
+		return checksMsg{signature: signature, findings: changes.Findings, complexity: deltas}
+	}
+}
+
+// showsWorktreeLines reports whether the new side of the current diff has
+// the working tree's line numbers, which findings and coverage use. Staged
+// diffs only share them when the file has no unstaged changes.
+func (m *Model) showsWorktreeLines() bool {
+	file := m.currentFile
+	return file != nil && hasWorktreeVersion(*file) && !m.showsFormatDiff(*file) &&
+		!(file.Staged && m.hasUnstagedChanges(file.Path))
+}
+
+// updateDiffFindings shows the findings for the current file in the diff.
+// A deleted file only has findings about the whole file.
+func (m *Model) updateDiffFindings() {
+	file := m.currentFile
+	deleted := file != nil && file.Deleted && file.Commit == ""
+	if !m.showsWorktreeLines() && !deleted {
+		m.diffView.SetFindings(nil)
+		return
+	}
+
+	var findings []checks.Finding
+	for _, f := range m.findings {
+		if f.Path == file.Path && (!deleted || f.Line == 0) {
+			findings = append(findings, f)
+		}
+	}
+	m.diffView.SetFindings(findings)
+}
+
+func (m *Model) hasUnstagedChanges(path string) bool {
+	for _, file := range m.files {
+		if file.Path == path && !file.Staged {
+			return true
+		}
+	}
+	return false
+}
+
+// setFindingMarks flags files with compile errors, secrets, and test files
+// the change weakened, in the file list.
+func (m *Model) setFindingMarks() {
+	marks := make(map[string]string)
+	for _, f := range m.findings {
+		switch {
+		case f.Suppressed:
+		case f.IsCompileError():
+			marks[f.Path] = "build"
+		case f.IsSecret() && marks[f.Path] != "build":
+			marks[f.Path] = "secret"
+		case f.IsWeakenedTest() && marks[f.Path] == "":
+			marks[f.Path] = "weak"
+		}
+	}
+	m.fileList.SetMarks(markFindings, marks)
+}
+
+// findingsSummary is the status bar item for the last checks run. A
+// broken build names its first error, which may be in a file the change
+// did not touch. Suppressed findings are not counted.
+func (m *Model) findingsSummary() string {
+	var first *checks.Finding
+	errors := 0
+	var active []checks.Finding
+	for i, f := range m.findings {
+		if f.IsCompileError() {
+			if first == nil {
+				first = &m.findings[i]
+			}
+			errors++
+		}
+		if !f.Suppressed {
+			active = append(active, f)
+		}
+	}
+	if first != nil {
+		msg := fmt.Sprintf("✗ %s:%d: %s", first.Path, first.Line, first.Message)
+		if errors > 1 {
+			msg += fmt.Sprintf(" (+%d)", errors-1)
+		}
+		return m.styles.FindingHigh.Render(truncate(msg, m.width/3))
+	}
+
+	if len(active) == 0 {
+		return ""
+	}
+	worst := checks.SeverityLow
+	for _, f := range active {
+		worst = max(worst, f.Severity)
+	}
+	msg := "1 finding"
+	if n := len(active); n > 1 {
+		msg = fmt.Sprintf("%d findings", n)
+	}
+	return m.diffView.findingStyle(worst).Render(msg)
+}
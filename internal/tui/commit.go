@@ -0,0 +1,242 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"grua/internal/git"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxSummaryFiles limits the staged file summary in the commit dialog.
+const maxSummaryFiles = 10
+
+// CommitDialog collects a commit message and commits the staged changes.
+type CommitDialog struct {
+	textarea textarea.Model
+	staged   []git.FileStatus
+	amend    bool
+	running  bool
+	// output holds what git and its hooks printed on the last failed
+	// attempt.
+	output string
+	// editor overrides $VISUAL and $EDITOR when set.
+	editor string
+	styles *Styles
+	keys   KeyMap
+	width  int
+	height int
+}
+
+type stagedFilesMsg struct {
+	files []git.FileStatus
+	err   error
+}
+
+type headMessageMsg struct {
+	message string
+}
+
+type commitDoneMsg struct {
+	output string
+	err    error
+}
+
+type editorDoneMsg struct {
+	message string
+	err     error
+}
+
+// headMessageRequestMsg asks the model for the HEAD commit message to
+// prefill an amend.
+type headMessageRequestMsg struct{}
+
+// commitRequestMsg asks the model to run git commit.
+type commitRequestMsg struct {
+	message string
+	amend   bool
+}
+
+func NewCommitDialog(styles *Styles, keys KeyMap) *CommitDialog {
+	ta := textarea.New()
+	ta.Placeholder = "Commit message"
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+
+	return &CommitDialog{
+		textarea: ta,
+		styles:   styles,
+		keys:     keys,
+	}
+}
+
+func (c *CommitDialog) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+	c.textarea.SetWidth(min(width-8, 100))
+	c.textarea.SetHeight(max(min(height-maxSummaryFiles-14, 12), 3))
+}
+
+// Open resets the dialog for a new commit.
+func (c *CommitDialog) Open() tea.Cmd {
+	c.textarea.Reset()
+	c.staged = nil
+	c.amend = false
+	c.running = false
+	c.output = ""
+	return c.textarea.Focus()
+}
+
+func (c *CommitDialog) SetStaged(files []git.FileStatus) {
+	c.staged = files
+}
+
+// SetMessage replaces the message, e.g. after editing it in $EDITOR.
+func (c *CommitDialog) SetMessage(message string) {
+	c.textarea.SetValue(strings.TrimRight(message, "\n"))
+}
+
+// Message returns the message typed so far.
+func (c *CommitDialog) Message() string {
+	return c.textarea.Value()
+}
+
+// Finish records the result of a commit attempt. It returns true if the
+// dialog can close.
+func (c *CommitDialog) Finish(output string, err error) bool {
+	c.running = false
+	if err != nil {
+		c.output = output
+		if c.output == "" {
+			c.output = err.Error()
+		}
+		return false
+	}
+	return true
+}
+
+func (c *CommitDialog) Update(msg tea.Msg) (*CommitDialog, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || c.running {
+		return c, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, c.keys.CommitSubmit):
+		message := c.textarea.Value()
+		if strings.TrimSpace(message) == "" {
+			c.output = "Aborting commit due to empty commit message."
+			return c, nil
+		}
+		c.running = true
+		c.output = ""
+		amend := c.amend
+		return c, func() tea.Msg { return commitRequestMsg{message: message, amend: amend} }
+	case key.Matches(keyMsg, c.keys.CommitAmend):
+		c.amend = !c.amend
+		if c.amend && strings.TrimSpace(c.textarea.Value()) == "" {
+			return c, func() tea.Msg { return headMessageRequestMsg{} }
+		}
+		return c, nil
+	case key.Matches(keyMsg, c.keys.CommitEditor):
+		return c, c.openEditor()
+	}
+
+	var cmd tea.Cmd
+	c.textarea, cmd = c.textarea.Update(msg)
+	return c, cmd
+}
+
+// openEditor suspends the program and edits the message in $EDITOR.
+func (c *CommitDialog) openEditor() tea.Cmd {
+	f, err := os.CreateTemp("", "grua-COMMIT_EDITMSG-*")
+	if err != nil {
+		return func() tea.Msg { return editorDoneMsg{err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(c.textarea.Value())
+	f.Close()
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return editorDoneMsg{err: err} }
+	}
+
+	editor := editorCommand(c.editor)
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return editorDoneMsg{err: err}
+		}
+		content, err := os.ReadFile(path)
+		return editorDoneMsg{message: string(content), err: err}
+	})
+}
+
+func (c *CommitDialog) View() string {
+	var b strings.Builder
+	center := lipgloss.NewStyle().Width(c.width).Align(lipgloss.Center)
+	dim := lipgloss.NewStyle().Foreground(ColorDim)
+
+	title := "Commit"
+	if c.amend {
+		title = "Amend last commit"
+	}
+	b.WriteString("\n")
+	b.WriteString(center.Render(lipgloss.NewStyle().Foreground(ColorTitle).Bold(true).Render(title)))
+	b.WriteString("\n\n")
+
+	var summary []string
+	if len(c.staged) == 0 {
+		summary = append(summary, dim.Italic(true).Render("Nothing staged"))
+	}
+	for i, file := range c.staged {
+		if i == maxSummaryFiles {
+			summary = append(summary, dim.Render(fmt.Sprintf("… and %d more", len(c.staged)-i)))
+			break
+		}
+		summary = append(summary, c.styles.StatusBadge.Render(fmt.Sprintf("%-4s", statusBadge(file)))+
+			lipgloss.NewStyle().Foreground(ColorFg).Render(file.Path))
+	}
+
+	body := []string{
+		c.styles.StagedHeader.Render(fmt.Sprintf("STAGED (%d)", len(c.staged))),
+		strings.Join(summary, "\n"),
+		"",
+		c.textarea.View(),
+		"",
+	}
+
+	amendBox := "[ ]"
+	if c.amend {
+		amendBox = "[x]"
+	}
+	hints := []string{
+		c.keys.CommitSubmit.Help().Key + " commit",
+		c.keys.CommitEditor.Help().Key + " $EDITOR",
+		c.keys.CommitAmend.Help().Key + " " + amendBox + " amend",
+		"esc cancel",
+	}
+	body = append(body, dim.Render(strings.Join(hints, "  ·  ")))
+
+	if c.running {
+		body = append(body, "", dim.Italic(true).Render("Committing…"))
+	} else if c.output != "" {
+		body = append(body, "", lipgloss.NewStyle().Foreground(ColorRemovedFg).Render(c.output))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorSelected).
+		Padding(0, 1).
+		Render(strings.Join(body, "\n"))
+	b.WriteString(center.Render(box))
+
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCommitDialogSubmit(t *testing.T) {
+	c := NewCommitDialog(NewStyles(), DefaultKeyMap())
+	c.SetSize(80, 40)
+	c.Open()
+
+	submit := tea.KeyMsg{Type: tea.KeyCtrlS}
+	if _, cmd := c.Update(submit); cmd != nil || c.output == "" {
+		t.Errorf("empty message: cmd = %v, output = %q, want it refused", cmd, c.output)
+	}
+
+	c.SetMessage("Fix the parser\n\n")
+	if c.Message() != "Fix the parser" {
+		t.Errorf("Message = %q", c.Message())
+	}
+	_, cmd := c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a"), Alt: true})
+	if cmd != nil {
+		t.Errorf("amend with a message asked for HEAD's: %#v", cmd())
+	}
+	_, cmd = c.Update(submit)
+	if msg, ok := cmd().(commitRequestMsg); !ok || msg.message != "Fix the parser" || !msg.amend {
+		t.Errorf("submit = %#v, want an amend request", cmd())
+	}
+	if _, cmd := c.Update(submit); cmd != nil {
+		t.Error("submitted twice while running")
+	}
+
+	if c.Finish("", errors.New("exit status 1")) || c.output != "exit status 1" {
+		t.Errorf("failed commit: output = %q", c.output)
+	}
+	if !c.Finish("[main abc123] Fix the parser", nil) {
+		t.Error("successful commit did not close the dialog")
+	}
+}
+
+func TestCommitDialogAmendEmpty(t *testing.T) {
+	c := NewCommitDialog(NewStyles(), DefaultKeyMap())
+	c.Open()
+	_, cmd := c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a"), Alt: true})
+	if cmd == nil {
+		t.Fatal("amend with no message did not ask for HEAD's")
+	}
+	if _, ok := cmd().(headMessageRequestMsg); !ok {
+		t.Errorf("amend = %#v, want headMessageRequestMsg", cmd())
+	}
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"grua/internal/checks"
+	"grua/internal/complexity"
+	"grua/internal/git"
+)
+
+// fileComplexity compares the complexity of the functions a change
+// touched in a file with their versions in HEAD. Functions not in HEAD
+// count as new.
+func (m *Model) fileComplexity(file git.FileStatus, added []git.LineRange) []complexity.Delta {
+	newSrc, err := os.ReadFile(filepath.Join(m.repoPath, file.Path))
+	if err != nil {
+		return nil
+	}
+	newFuncs, err := complexity.Funcs(file.Path, newSrc)
+	if err != nil {
+		return nil
+	}
+
+	var oldFuncs []complexity.Func
+	if oldSrc := checks.HeadVersion(m.gitService, file); oldSrc != nil {
+		oldFuncs, _ = complexity.Funcs(file.Path, oldSrc)
+	}
+
+	return complexity.Compare(oldFuncs, newFuncs, func(line int) bool {
+		for _, r := range added {
+			if line >= r.Start && line <= r.End {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// updateDiffComplexity shows the complexity deltas of the current file in
+// the diff.
+func (m *Model) updateDiffComplexity() {
+	if !m.showsWorktreeLines() {
+		m.diffView.SetComplexity(nil)
+		return
+	}
+	m.diffView.SetComplexity(m.complexity[m.currentFile.Path])
+}
+
+// complexityNote describes a delta briefly, for the function's header
+// line.
+func complexityNote(d complexity.Delta) string {
+	if d.IsNew() {
+		return fmt.Sprintf("new · cyclo %d · cog %d", d.New.Cyclomatic, d.New.Cognitive)
+	}
+	return fmt.Sprintf("cyclo %+d · cog %+d", d.CyclomaticDelta(), d.CognitiveDelta())
+}
+
+// complexitySummary describes a delta in full, for the summary above the
+// diff.
+func complexitySummary(d complexity.Delta) string {
+	if d.IsNew() {
+		return fmt.Sprintf("%s: new, cyclomatic %d, cognitive %d", d.Name, d.New.Cyclomatic, d.New.Cognitive)
+	}
+	return fmt.Sprintf("%s: cyclomatic %d → %d (%+d), cognitive %d → %d (%+d)", d.Name,
+		d.Old.Cyclomatic, d.New.Cyclomatic, d.CyclomaticDelta(),
+		d.Old.Cognitive, d.New.Cognitive, d.CognitiveDelta())
+}
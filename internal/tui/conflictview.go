@@ -0,0 +1,341 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"grua/internal/git"
+	"grua/internal/highlight"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// conflictContext is how many lines around each region are shown.
+const conflictContext = 3
+
+// ConflictView shows the conflict regions of an unmerged file with ours,
+// base and theirs side by side, and lets the user pick a side per region.
+type ConflictView struct {
+	conflict    *git.Conflict
+	viewport    viewport.Model
+	highlighter *highlight.Highlighter
+	styles      *Styles
+	keys        KeyMap
+	width       int
+	height      int
+	ready       bool
+
+	// region is the selected region, and regionRows the rendered row of
+	// each region's header.
+	region     int
+	regionRows []int
+}
+
+// resolveMsg asks the model to resolve a region with the chosen side.
+type resolveMsg struct {
+	path   string
+	region git.ConflictRegion
+	side   git.ConflictSide
+}
+
+// resolveDeletionMsg asks the model to resolve a modify/delete conflict by
+// keeping the modified file or removing it.
+type resolveDeletionMsg struct {
+	path string
+	keep bool
+}
+
+// markResolvedMsg asks the model to stage a file whose markers are gone.
+type markResolvedMsg struct {
+	path string
+}
+
+func NewConflictView(styles *Styles, keys KeyMap) *ConflictView {
+	return &ConflictView{
+		styles:      styles,
+		keys:        keys,
+		highlighter: highlight.New(),
+	}
+}
+
+func (c *ConflictView) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+
+	viewportHeight := height - 3
+	viewportWidth := width - 4
+	if !c.ready {
+		c.viewport = viewport.New(viewportWidth, viewportHeight)
+		c.ready = true
+	} else {
+		c.viewport.Width = viewportWidth
+		c.viewport.Height = viewportHeight
+	}
+
+	if c.conflict != nil {
+		c.render()
+	}
+}
+
+func (c *ConflictView) SetConflict(conflict *git.Conflict) {
+	isNewFile := c.conflict == nil || conflict == nil || c.conflict.Path != conflict.Path
+	c.conflict = conflict
+
+	prevYOffset := c.viewport.YOffset
+	c.render()
+	if isNewFile {
+		c.region = 0
+		c.viewport.GotoTop()
+		return
+	}
+	c.region = min(c.region, max(len(conflict.Regions)-1, 0))
+	c.viewport.SetYOffset(prevYOffset)
+}
+
+func (c *ConflictView) render() {
+	c.regionRows = nil
+	if c.conflict == nil || !c.ready {
+		c.viewport.SetContent("")
+		return
+	}
+
+	contentWidth := c.width - 6
+	colWidth := (contentWidth - 6) / 3
+	sep := lipgloss.NewStyle().Foreground(ColorBorder).Render(" │ ")
+
+	var rows []string
+	for i, r := range c.conflict.Regions {
+		c.regionRows = append(c.regionRows, len(rows))
+
+		header := fmt.Sprintf("Conflict %d/%d · lines %d-%d", i+1, len(c.conflict.Regions), r.Start+1, r.End+1)
+		if i == c.region {
+			rows = append(rows, c.styles.ConflictSelected.Render("▶ "+header))
+		} else {
+			rows = append(rows, c.highlighter.HighlightHunkHeader("  "+header))
+		}
+
+		for n := max(r.Start-conflictContext, 0); n < r.Start; n++ {
+			rows = append(rows, c.contextLine(n, contentWidth))
+		}
+
+		labels := []string{"OURS " + r.OursLabel, "BASE", "THEIRS " + r.TheirsLabel}
+		var cells []string
+		for _, label := range labels {
+			cells = append(cells, c.styles.ConflictLabel.Width(colWidth).Render(truncate(label, colWidth)))
+		}
+		rows = append(rows, strings.Join(cells, sep))
+
+		height := max(len(r.Ours), len(r.Base), len(r.Theirs))
+		for k := 0; k < height; k++ {
+			cells = cells[:0]
+			for _, side := range [][]string{r.Ours, r.Base, r.Theirs} {
+				text := ""
+				if k < len(side) {
+					text = truncate(expandTabs(side[k]), colWidth)
+				}
+				cells = append(cells, c.highlighter.HighlightLine(text, highlight.LineContext, colWidth))
+			}
+			rows = append(rows, strings.Join(cells, sep))
+		}
+
+		for n := r.End + 1; n <= min(r.End+conflictContext, len(c.conflict.Lines)-1); n++ {
+			rows = append(rows, c.contextLine(n, contentWidth))
+		}
+		rows = append(rows, "")
+	}
+
+	c.viewport.SetContent(strings.Join(rows, "\n"))
+}
+
+func (c *ConflictView) contextLine(n, width int) string {
+	lineNum := c.styles.LineNumber.Render(fmt.Sprintf("%4d ", n+1))
+	text := truncate(expandTabs(c.conflict.Lines[n]), width-7)
+	return lineNum + "  " + c.highlighter.HighlightLine(text, highlight.LineContext, 0)
+}
+
+// selectRegion selects region i and scrolls its header to the top.
+func (c *ConflictView) selectRegion(i int) {
+	if i < 0 || i >= len(c.regionRows) {
+		return
+	}
+	c.region = i
+	c.render()
+	c.viewport.SetYOffset(c.regionRows[i])
+}
+
+func (c *ConflictView) selectedRegion() *git.ConflictRegion {
+	if c.conflict == nil || c.region >= len(c.conflict.Regions) {
+		return nil
+	}
+	return &c.conflict.Regions[c.region]
+}
+
+func (c *ConflictView) resolve(side git.ConflictSide) tea.Cmd {
+	if c.conflict != nil && c.conflict.DeletedBy() != "" {
+		return c.resolveDeletion(side)
+	}
+	region := c.selectedRegion()
+	if region == nil {
+		return nil
+	}
+	msg := resolveMsg{path: c.conflict.Path, region: *region, side: side}
+	return func() tea.Msg { return msg }
+}
+
+// resolveDeletion does what the chosen side did to the file of a
+// modify/delete conflict: the side that modified it keeps it, and the side
+// that deleted it removes it.
+func (c *ConflictView) resolveDeletion(side git.ConflictSide) tea.Cmd {
+	if side == git.SideBoth {
+		return nil
+	}
+	deletedBy := c.conflict.DeletedBy()
+	keep := deletedBy == "us" && side == git.SideTheirs || deletedBy == "them" && side == git.SideOurs
+	msg := resolveDeletionMsg{path: c.conflict.Path, keep: keep}
+	return func() tea.Msg { return msg }
+}
+
+func (c *ConflictView) Update(msg tea.Msg) (*ConflictView, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, c.keys.Up):
+			c.viewport.ScrollUp(1)
+		case key.Matches(msg, c.keys.Down):
+			c.viewport.ScrollDown(1)
+		case key.Matches(msg, c.keys.Top):
+			c.viewport.GotoTop()
+		case key.Matches(msg, c.keys.Bottom):
+			c.viewport.GotoBottom()
+		case key.Matches(msg, c.keys.PageUp):
+			c.viewport.HalfViewUp()
+		case key.Matches(msg, c.keys.PageDown):
+			c.viewport.HalfViewDown()
+		case key.Matches(msg, c.keys.NextHunk), key.Matches(msg, c.keys.NextChange):
+			c.selectRegion(c.region + 1)
+		case key.Matches(msg, c.keys.PrevHunk), key.Matches(msg, c.keys.PrevChange):
+			c.selectRegion(c.region - 1)
+		case key.Matches(msg, c.keys.TakeOurs):
+			cmd = c.resolve(git.SideOurs)
+		case key.Matches(msg, c.keys.TakeTheirs):
+			cmd = c.resolve(git.SideTheirs)
+		case key.Matches(msg, c.keys.TakeBoth):
+			cmd = c.resolve(git.SideBoth)
+		case key.Matches(msg, c.keys.MarkResolved):
+			// A file deleted on one side has no markers, but staging it
+			// is not a resolution the user chose.
+			if c.conflict != nil && len(c.conflict.Regions) == 0 && c.conflict.DeletedBy() == "" {
+				path := c.conflict.Path
+				cmd = func() tea.Msg { return markResolvedMsg{path: path} }
+			}
+		default:
+			c.viewport, cmd = c.viewport.Update(msg)
+		}
+	default:
+		c.viewport, cmd = c.viewport.Update(msg)
+	}
+
+	return c, cmd
+}
+
+func (c *ConflictView) View(active bool) string {
+	title := "No file selected"
+	if c.conflict != nil {
+		title = c.conflict.Path
+	}
+	titleStyled := c.styles.DiffTitle.Render(title)
+	if c.conflict != nil {
+		titleStyled += " " + c.styles.DiffFileLabel.Render(conflictDescription(c.conflict.Status))
+	}
+
+	var content string
+	switch {
+	case c.conflict == nil:
+		content = lipgloss.NewStyle().
+			Foreground(ColorDim).
+			Italic(true).
+			Render("Loading conflict...")
+	case c.conflict.DeletedBy() != "":
+		content = lipgloss.NewStyle().
+			Foreground(ColorDim).
+			Italic(true).
+			Render(deletionHelp(c.conflict.DeletedBy()))
+	case len(c.conflict.Regions) == 0:
+		content = lipgloss.NewStyle().
+			Foreground(ColorDim).
+			Italic(true).
+			Render("No conflict markers left. Press S to mark the file resolved.")
+	default:
+		content = c.viewport.View()
+	}
+
+	fullContent := titleStyled + "\n" + content
+
+	borderStyle := c.styles.DiffBorder
+	if active {
+		borderStyle = c.styles.DiffBorderActive
+	}
+
+	return borderStyle.
+		Width(c.width).
+		Height(c.height).
+		Render(fullContent)
+}
+
+// conflictDescription explains a two-letter unmerged status.
+func conflictDescription(status string) string {
+	switch status {
+	case "UU":
+		return "both modified"
+	case "AA":
+		return "both added"
+	case "DD":
+		return "both deleted"
+	case "AU":
+		return "added by us"
+	case "UA":
+		return "added by them"
+	case "DU":
+		return "deleted by us"
+	case "UD":
+		return "deleted by them"
+	default:
+		return status
+	}
+}
+
+// deletionHelp explains how to resolve a modify/delete conflict.
+func deletionHelp(deletedBy string) string {
+	switch deletedBy {
+	case "us":
+		return "Deleted by us and modified by them. Press o to delete the file or t to keep their version."
+	case "them":
+		return "Modified by us and deleted by them. Press o to keep our version or t to delete the file."
+	default:
+		return "Deleted on both sides. Press o or t to delete the file."
+	}
+}
+
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
+
+// truncate shortens s to at most width runes.
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
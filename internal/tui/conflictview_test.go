@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"testing"
+
+	"grua/internal/git"
+)
+
+func TestConflictViewDeletion(t *testing.T) {
+	tests := []struct {
+		status string
+		key    string
+		want   *resolveDeletionMsg
+	}{
+		{"DU", "o", &resolveDeletionMsg{path: "f.go", keep: false}},
+		{"DU", "t", &resolveDeletionMsg{path: "f.go", keep: true}},
+		{"UD", "o", &resolveDeletionMsg{path: "f.go", keep: true}},
+		{"UD", "t", &resolveDeletionMsg{path: "f.go", keep: false}},
+		{"DD", "t", &resolveDeletionMsg{path: "f.go", keep: false}},
+		{"DU", "a", nil},
+		// Staging is not offered, as the file may not exist.
+		{"DU", "S", nil},
+	}
+	for _, tt := range tests {
+		c := NewConflictView(NewStyles(), DefaultKeyMap())
+		c.SetSize(80, 20)
+		c.SetConflict(&git.Conflict{Path: "f.go", Status: tt.status})
+
+		_, cmd := c.Update(keyPress(tt.key))
+		if tt.want == nil {
+			if cmd != nil {
+				t.Errorf("%s %s: got %#v, want no command", tt.status, tt.key, cmd())
+			}
+			continue
+		}
+		if cmd == nil {
+			t.Errorf("%s %s: got no command", tt.status, tt.key)
+			continue
+		}
+		if got, ok := cmd().(resolveDeletionMsg); !ok || got != *tt.want {
+			t.Errorf("%s %s: got %#v, want %#v", tt.status, tt.key, cmd(), *tt.want)
+		}
+	}
+}
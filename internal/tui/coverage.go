@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+
+	"grua/internal/git"
+	"grua/internal/gotest"
+)
+
+// addedCoverage returns the percentage of the statement lines among added
+// that ran. ok is false if none of the added lines hold statements.
+func addedCoverage(cov gotest.Coverage, added []git.LineRange) (percent int, ok bool) {
+	var lines, covered int
+	for line, ran := range cov {
+		for _, r := range added {
+			if line >= r.Start && line <= r.End {
+				lines++
+				if ran {
+					covered++
+				}
+				break
+			}
+		}
+	}
+	if lines == 0 {
+		return 0, false
+	}
+	return covered * 100 / lines, true
+}
+
+// setCoverageMarks shows the coverage of each file's added lines in the
+// file list.
+func (m *Model) setCoverageMarks() {
+	marks := make(map[string]string, len(m.coveragePercent))
+	for path, percent := range m.coveragePercent {
+		marks[path] = fmt.Sprintf("cov %d%%", percent)
+	}
+	m.fileList.SetMarks(markCoverage, marks)
+}
+
+// dropStaleCoverage forgets the coverage of the last test run once the
+// changed files differ from those tested, as its line numbers no longer
+// apply.
+func (m *Model) dropStaleCoverage(files []git.FileStatus) {
+	if m.coverage == nil || m.checkSignature(files) == m.testedSig {
+		return
+	}
+	m.coverage = nil
+	m.coveragePercent = nil
+	m.setCoverageMarks()
+	m.updateDiffCoverage()
+}
+
+// updateDiffCoverage shows the coverage of the current file in the diff.
+func (m *Model) updateDiffCoverage() {
+	if !m.showsWorktreeLines() {
+		m.diffView.SetCoverage(nil)
+		return
+	}
+	m.diffView.SetCoverage(m.coverage[m.currentFile.Path])
+}
@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"testing"
+
+	"grua/internal/git"
+	"grua/internal/gotest"
+)
+
+func TestAddedCoverage(t *testing.T) {
+	cov := gotest.Coverage{2: true, 3: false, 4: true, 10: false, 20: true}
+	tests := []struct {
+		name    string
+		added   []git.LineRange
+		percent int
+		ok      bool
+	}{
+		{"all covered", []git.LineRange{{Start: 1, End: 2}}, 100, true},
+		{"none covered", []git.LineRange{{Start: 3, End: 3}}, 0, true},
+		{"rounded down", []git.LineRange{{Start: 2, End: 4}}, 66, true},
+		{"several ranges", []git.LineRange{{Start: 3, End: 3}, {Start: 10, End: 20}}, 33, true},
+		{"no statements", []git.LineRange{{Start: 5, End: 9}}, 0, false},
+		{"no added lines", nil, 0, false},
+	}
+	for _, tt := range tests {
+		percent, ok := addedCoverage(cov, tt.added)
+		if percent != tt.percent || ok != tt.ok {
+			t.Errorf("%s: addedCoverage = %d, %v, want %d, %v", tt.name, percent, ok, tt.percent, tt.ok)
+		}
+	}
+}
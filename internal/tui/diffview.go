@@ -2,9 +2,13 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"grua/internal/checks"
+	"grua/internal/complexity"
 	"grua/internal/git"
+	"grua/internal/gotest"
 	"grua/internal/highlight"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -24,6 +28,60 @@ type DiffView struct {
 	height      int
 	ready       bool
 	filePath    string
+	flags       []string
+	// blame, when set, adds a gutter naming the commit behind each
+	// removed and context line.
+	blame *git.Blame
+	// findings holds check findings on the new side of the diff by line;
+	// line 0 holds those about the whole file.
+	findings map[int][]checks.Finding
+	// suppressed holds the findings waived by ignore comments, listed in
+	// a group above the diff that is collapsed unless showSuppressed.
+	suppressed     []checks.Finding
+	showSuppressed bool
+	// coverage, when set, marks each added line as covered or not by
+	// the last test run.
+	coverage gotest.Coverage
+	// complexity lists the complexity deltas of the functions the change
+	// touched, summarised above the diff and noted on each function's
+	// first line.
+	complexity []complexity.Delta
+
+	// rows maps each rendered line in the viewport back to its hunk and
+	// diff line, and rendered holds those lines before the cursor gutter
+	// is applied.
+	rows     []diffRow
+	rendered []string
+	cursor   int
+}
+
+// diffRow identifies what a rendered line shows. line is -1 for hunk
+// headers and spacer lines, and hunk is -1 for the summaries listed above
+// the first hunk.
+type diffRow struct {
+	hunk int
+	line int
+}
+
+// preambleRow is the row of a line shown above the first hunk.
+var preambleRow = diffRow{hunk: -1, line: -1}
+
+// jumpTarget says where to place the cursor once a diff has loaded.
+type jumpTarget int
+
+const (
+	jumpNone jumpTarget = iota
+	jumpFirstHunk
+	jumpLastHunk
+	jumpFirstChange
+	jumpLastChange
+)
+
+// fileJumpMsg asks the model to move to the next or previous file because
+// hunk or change navigation ran past the end of the current diff.
+type fileJumpMsg struct {
+	forward bool
+	target  jumpTarget
 }
 
 func NewDiffView(styles *Styles, keys KeyMap) *DiffView {
@@ -58,7 +116,13 @@ func (d *DiffView) SetSize(width, height int) {
 
 func (d *DiffView) SetDiff(diff *git.FileDiff) {
 	isNewFile := d.diff == nil || diff == nil ||
-		d.diff.Path != diff.Path || d.diff.Staged != diff.Staged
+		d.diff.Path != diff.Path || d.diff.Staged != diff.Staged || d.diff.Commit != diff.Commit
+
+	var anchor *git.DiffLine
+	if sel := d.SelectedLine(); sel != nil && !isNewFile {
+		line := *sel
+		anchor = &line
+	}
 
 	d.diff = diff
 	if diff != nil {
@@ -68,11 +132,24 @@ func (d *DiffView) SetDiff(diff *git.FileDiff) {
 	}
 
 	prevYOffset := d.viewport.YOffset
+	prevCursor := d.cursor
 	d.renderDiff()
 
+	// Keep the cursor on the same line when context is revealed or the
+	// diff is refreshed, shifting the view by the same amount.
+	if anchor != nil {
+		if row := d.findRow(*anchor); row >= 0 {
+			prevYOffset += row - prevCursor
+			prevCursor = row
+		}
+	}
+
 	if isNewFile {
+		d.cursor = 0
 		d.viewport.GotoTop()
+		d.refreshContent()
 	} else {
+		d.cursor = min(prevCursor, max(len(d.rows)-1, 0))
 		maxYOffset := d.viewport.TotalLineCount() - d.viewport.Height
 		if maxYOffset < 0 {
 			maxYOffset = 0
@@ -82,24 +159,135 @@ func (d *DiffView) SetDiff(diff *git.FileDiff) {
 		} else {
 			d.viewport.SetYOffset(prevYOffset)
 		}
+		d.refreshContent()
 	}
 }
 
+// SetBlame sets the blame shown in the gutter; nil hides the gutter.
+func (d *DiffView) SetBlame(blame *git.Blame) {
+	d.blame = blame
+	d.renderDiff()
+}
+
+// SetFindings sets the check findings marked in the diff; nil clears them.
+func (d *DiffView) SetFindings(findings []checks.Finding) {
+	d.findings = nil
+	d.suppressed = nil
+	for _, f := range findings {
+		if f.Suppressed {
+			d.suppressed = append(d.suppressed, f)
+			continue
+		}
+		if d.findings == nil {
+			d.findings = make(map[int][]checks.Finding)
+		}
+		d.findings[f.Line] = append(d.findings[f.Line], f)
+	}
+	d.renderDiff()
+}
+
+// ToggleSuppressed expands or collapses the group of suppressed findings.
+func (d *DiffView) ToggleSuppressed() {
+	d.showSuppressed = !d.showSuppressed
+	d.renderDiff()
+}
+
+// SetCoverage sets the coverage marked on added lines; nil hides it.
+func (d *DiffView) SetCoverage(coverage gotest.Coverage) {
+	d.coverage = coverage
+	d.renderDiff()
+}
+
+// SetComplexity sets the complexity deltas shown; nil hides them.
+func (d *DiffView) SetComplexity(deltas []complexity.Delta) {
+	d.complexity = deltas
+	d.renderDiff()
+}
+
+// SetFlags sets the diff modes listed next to the title.
+func (d *DiffView) SetFlags(flags []string) {
+	d.flags = flags
+}
+
 func (d *DiffView) renderDiff() {
+	d.rows = nil
+	d.rendered = nil
 	if d.diff == nil || !d.ready {
 		d.viewport.SetContent("")
 		return
 	}
 
 	var lines []string
-	contentWidth := d.width - 6
+	// rowWidth is the viewport width less the cursor gutter. Lines start
+	// with the line number and change indicator.
+	rowWidth := d.width - 5
+	contentWidth := rowWidth - 8
+	// Inline finding messages line up with the code.
+	indent := 8
+	if d.blame != nil {
+		contentWidth -= blameWidth
+		indent += blameWidth
+	}
+	if d.findings != nil {
+		contentWidth--
+		indent++
+	}
+	if d.coverage != nil {
+		contentWidth--
+		indent++
+	}
 
-	for _, hunk := range d.diff.Hunks {
+	notes := make(map[int]complexity.Delta, len(d.complexity))
+	if len(d.diff.Hunks) > 0 {
+		for _, delta := range d.complexity {
+			notes[delta.Line] = delta
+			msg := truncate(complexitySummary(delta), max(rowWidth-1, 10))
+			lines = append(lines, " "+d.complexityStyle(delta).Render(msg))
+			d.rows = append(d.rows, preambleRow)
+		}
+	}
+
+	// Findings about the whole file, or on lines outside the hunks, are
+	// listed at the top.
+	if len(d.diff.Hunks) > 0 {
+		for _, f := range d.unplacedFindings() {
+			msg := f.Rule + ": " + f.Message
+			if f.Line > 0 {
+				msg = fmt.Sprintf("line %d: %s", f.Line, msg)
+			}
+			style := d.findingStyle(f.Severity)
+			lines = append(lines, " "+style.Render(truncate(msg, max(rowWidth-1, 10))))
+			d.rows = append(d.rows, preambleRow)
+		}
+	}
+
+	// Suppressed findings follow, as a group collapsed to one line.
+	if len(d.diff.Hunks) > 0 && len(d.suppressed) > 0 {
+		header := fmt.Sprintf("▸ %d suppressed (s to show)", len(d.suppressed))
+		if d.showSuppressed {
+			header = fmt.Sprintf("▾ %d suppressed", len(d.suppressed))
+		}
+		lines = append(lines, " "+d.styles.CommitMeta.Render(header))
+		d.rows = append(d.rows, preambleRow)
+		if d.showSuppressed {
+			for _, f := range d.suppressed {
+				msg := fmt.Sprintf("line %d: %s: %s", f.Line, f.Rule, f.Message)
+				if f.Reason != "" {
+					msg += " — " + f.Reason
+				}
+				lines = append(lines, "   "+d.styles.CommitMeta.Render(truncate(msg, max(rowWidth-3, 10))))
+				d.rows = append(d.rows, preambleRow)
+			}
+		}
+	}
+
+	for h, hunk := range d.diff.Hunks {
 		header := d.highlighter.HighlightHunkHeader(hunk.Header)
 		lines = append(lines, header)
 		lines = append(lines, "")
+		d.rows = append(d.rows, diffRow{hunk: h, line: -1}, diffRow{hunk: h, line: -1})
 
-		for _, line := range hunk.Lines {
+		for i, line := range hunk.Lines {
 			var lineNum string
 			switch line.Type {
 			case git.LineAdded:
@@ -113,25 +301,43 @@ func (d *DiffView) renderDiff() {
 			lineNumStyled := d.styles.LineNumber.Render(lineNum)
 
 			var hlType highlight.LineType
-			switch line.Type {
-			case git.LineAdded:
+			switch {
+			case line.Type == git.LineAdded && line.Moved:
+				hlType = highlight.LineMovedAdded
+			case line.Type == git.LineRemoved && line.Moved:
+				hlType = highlight.LineMovedRemoved
+			case line.Type == git.LineAdded:
 				hlType = highlight.LineAdded
-			case git.LineRemoved:
+			case line.Type == git.LineRemoved:
 				hlType = highlight.LineRemoved
 			default:
 				hlType = highlight.LineContext
 			}
 
 			content := d.highlighter.HighlightLine(line.Content, hlType, contentWidth)
+			if delta, ok := notes[line.NewLineNum]; ok && line.Type != git.LineRemoved {
+				content = d.withNote(line.Content, hlType, contentWidth, complexityNote(delta), d.complexityStyle(delta))
+			}
 
 			var indicator string
-			switch line.Type {
-			case git.LineAdded:
+			switch {
+			case line.Moved:
+				bg := ColorMovedAddedBg
+				symbol := "+"
+				if line.Type == git.LineRemoved {
+					bg = ColorMovedRemovedBg
+					symbol = "-"
+				}
+				indicator = lipgloss.NewStyle().
+					Foreground(ColorMovedFg).
+					Background(bg).
+					Render(symbol)
+			case line.Type == git.LineAdded:
 				indicator = lipgloss.NewStyle().
 					Foreground(ColorAddedFg).
 					Background(ColorAddedBg).
 					Render("+")
-			case git.LineRemoved:
+			case line.Type == git.LineRemoved:
 				indicator = lipgloss.NewStyle().
 					Foreground(ColorRemovedFg).
 					Background(ColorRemovedBg).
@@ -141,15 +347,355 @@ func (d *DiffView) renderDiff() {
 			}
 
 			fullLine := lineNumStyled + indicator + " " + content
+			if d.coverage != nil {
+				fullLine = d.coverageMarker(line) + fullLine
+			}
+			var lineFindings []checks.Finding
+			if d.findings != nil {
+				if line.Type != git.LineRemoved {
+					lineFindings = d.findings[line.NewLineNum]
+				}
+				fullLine = d.findingMarker(lineFindings) + fullLine
+			}
+			if d.blame != nil {
+				fullLine = d.blameGutter(line) + fullLine
+			}
 			lines = append(lines, fullLine)
+			d.rows = append(d.rows, diffRow{hunk: h, line: i})
+
+			for _, f := range lineFindings {
+				lines = append(lines, d.findingMessage(f, indent, rowWidth-indent))
+				d.rows = append(d.rows, diffRow{hunk: h, line: -1})
+			}
 		}
 
 		lines = append(lines, "")
+		d.rows = append(d.rows, diffRow{hunk: h, line: -1})
+	}
+
+	if d.diff.Truncated && len(d.diff.Hunks) > 0 {
+		shown := 0
+		for _, hunk := range d.diff.Hunks {
+			shown += len(hunk.Lines)
+		}
+		// Git diffs are read no further than the cap, so their length is
+		// not known.
+		notice := fmt.Sprintf("… showing the first %d diff lines. Press L to load the rest.", shown)
+		if d.diff.TotalLines > shown {
+			notice = fmt.Sprintf("… showing %d of %d diff lines. Press L to load the rest.",
+				shown, d.diff.TotalLines)
+		}
+		lines = append(lines, d.styles.DiffFlag.Render(notice))
+		d.rows = append(d.rows, diffRow{hunk: len(d.diff.Hunks) - 1, line: -1})
+	}
+
+	d.rendered = lines
+	d.refreshContent()
+}
+
+// complexityStyle colours a delta by whether the function grew more
+// complex.
+func (d *DiffView) complexityStyle(delta complexity.Delta) lipgloss.Style {
+	switch {
+	case delta.IsNew() || delta.CognitiveDelta() > 0 || delta.CyclomaticDelta() > 0:
+		return d.styles.FindingMedium
+	case delta.CognitiveDelta() < 0 || delta.CyclomaticDelta() < 0:
+		return d.styles.Covered
+	default:
+		return d.styles.CommitMeta
 	}
+}
 
+// withNote highlights a line with a note at its right edge, if both fit.
+func (d *DiffView) withNote(content string, hlType highlight.LineType, width int, note string, style lipgloss.Style) string {
+	noteWidth := lipgloss.Width(note) + 1
+	line := d.highlighter.HighlightLine(content, hlType, width-noteWidth)
+	if lipgloss.Width(line) > width-noteWidth {
+		return d.highlighter.HighlightLine(content, hlType, width)
+	}
+	if bg, ok := highlight.Background(hlType); ok {
+		style = style.Background(bg)
+	}
+	return line + style.Render(" "+note)
+}
+
+// coverageMarker shows whether an added line ran in the last test run.
+// Lines without statements, and unchanged lines, are left blank.
+func (d *DiffView) coverageMarker(line git.DiffLine) string {
+	if line.Type != git.LineAdded {
+		return " "
+	}
+	ran, ok := d.coverage[line.NewLineNum]
+	switch {
+	case !ok:
+		return " "
+	case ran:
+		return d.styles.Covered.Render("▎")
+	default:
+		return d.styles.Uncovered.Render("▎")
+	}
+}
+
+// findingStyle is the style for findings of a severity.
+func (d *DiffView) findingStyle(severity checks.Severity) lipgloss.Style {
+	switch severity {
+	case checks.SeverityHigh:
+		return d.styles.FindingHigh
+	case checks.SeverityMedium:
+		return d.styles.FindingMedium
+	default:
+		return d.styles.FindingLow
+	}
+}
+
+// findingMarker is the gutter marker for a line's findings, coloured by
+// the most severe one.
+func (d *DiffView) findingMarker(findings []checks.Finding) string {
+	if len(findings) == 0 {
+		return " "
+	}
+	worst := findings[0].Severity
+	for _, f := range findings[1:] {
+		worst = max(worst, f.Severity)
+	}
+	return d.findingStyle(worst).Render("●")
+}
+
+// findingMessage renders a finding as an inline row below its line.
+func (d *DiffView) findingMessage(f checks.Finding, indent, width int) string {
+	msg := truncate(f.Rule+": "+f.Message, max(width, 10))
+	return strings.Repeat(" ", indent) + d.findingStyle(f.Severity).Render(msg)
+}
+
+// unplacedFindings returns the findings that no shown line carries, in
+// line order.
+func (d *DiffView) unplacedFindings() []checks.Finding {
+	shown := make(map[int]bool)
+	for _, hunk := range d.diff.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type != git.LineRemoved {
+				shown[line.NewLineNum] = true
+			}
+		}
+	}
+	var unplaced []checks.Finding
+	for line, findings := range d.findings {
+		if !shown[line] {
+			unplaced = append(unplaced, findings...)
+		}
+	}
+	slices.SortStableFunc(unplaced, func(a, b checks.Finding) int { return a.Line - b.Line })
+	return unplaced
+}
+
+// blameWidth is the width of the blame gutter: hash, author and date.
+const blameWidth = 28
+
+// blameGutter describes the commit behind a line. Added lines are not in
+// the blamed revision and get an empty gutter, and lines blamed in the
+// index that were staged since HEAD have no commit to name.
+func (d *DiffView) blameGutter(line git.DiffLine) string {
+	var commit *git.BlameCommit
+	if line.Type != git.LineAdded {
+		commit = d.blame.Line(line.OldLineNum)
+	}
+	if commit == nil {
+		return strings.Repeat(" ", blameWidth)
+	}
+	if !commit.Committed() {
+		return d.styles.CommitMeta.Render(fmt.Sprintf("%-*s", blameWidth, "staged, not committed"))
+	}
+	author := fmt.Sprintf("%-8s", truncate(commit.Author, 8))
+	return d.styles.CommitHash.Render(shortHash(commit.Hash)) + " " +
+		d.styles.BlameAuthor.Render(author) + " " +
+		d.styles.CommitMeta.Render(commit.Time.Format("2006-01-02")) + " "
+}
+
+// SelectedBlame returns the commit behind the line under the cursor, or
+// nil if blame is off or the line is not in the blamed revision.
+func (d *DiffView) SelectedBlame() *git.BlameCommit {
+	line := d.SelectedLine()
+	if d.blame == nil || line == nil || line.Type == git.LineAdded {
+		return nil
+	}
+	return d.blame.Line(line.OldLineNum)
+}
+
+// refreshContent pushes the rendered lines into the viewport with the
+// cursor gutter applied. It is cheap compared to renderDiff, so cursor
+// movement only calls this.
+func (d *DiffView) refreshContent() {
+	if len(d.rendered) == 0 {
+		d.viewport.SetContent("")
+		return
+	}
+
+	gutter := lipgloss.NewStyle().Foreground(ColorSelected).Render("▌")
+	lines := make([]string, len(d.rendered))
+	for i, line := range d.rendered {
+		if i == d.cursor {
+			lines[i] = gutter + line
+		} else {
+			lines[i] = " " + line
+		}
+	}
 	d.viewport.SetContent(strings.Join(lines, "\n"))
 }
 
+// setCursor moves the cursor to row and scrolls just enough to keep it
+// visible.
+func (d *DiffView) setCursor(row int) {
+	if len(d.rows) == 0 {
+		d.cursor = 0
+		return
+	}
+	d.cursor = max(0, min(row, len(d.rows)-1))
+
+	if d.cursor < d.viewport.YOffset {
+		d.viewport.SetYOffset(d.cursor)
+	} else if d.cursor >= d.viewport.YOffset+d.viewport.Height {
+		d.viewport.SetYOffset(d.cursor - d.viewport.Height + 1)
+	}
+	d.refreshContent()
+}
+
+// jumpTo places the cursor on row and scrolls it to the top of the view so
+// the hunk or change that follows is fully visible.
+func (d *DiffView) jumpTo(row int) {
+	d.viewport.SetYOffset(row)
+	d.setCursor(row)
+}
+
+// clampCursor pulls the cursor back into the visible area after the
+// viewport has been scrolled independently of it.
+func (d *DiffView) clampCursor() {
+	top := d.viewport.YOffset
+	bottom := top + d.viewport.Height - 1
+	if d.cursor < top {
+		d.cursor = top
+	} else if d.cursor > bottom {
+		d.cursor = bottom
+	}
+	d.cursor = max(0, min(d.cursor, len(d.rows)-1))
+	d.refreshContent()
+}
+
+// hunkStarts returns the row of each hunk header.
+func (d *DiffView) hunkStarts() []int {
+	var starts []int
+	for i, row := range d.rows {
+		if row.hunk >= 0 && row.line == -1 && (i == 0 || d.rows[i-1].hunk != row.hunk) {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// changeStarts returns the row of the first line of each run of added or
+// removed lines.
+func (d *DiffView) changeStarts() []int {
+	var starts []int
+	prevChanged := false
+	for i := range d.rows {
+		line := d.lineAt(i)
+		changed := line != nil && line.Type != git.LineContext
+		if changed && !prevChanged {
+			starts = append(starts, i)
+		}
+		prevChanged = changed
+	}
+	return starts
+}
+
+// findRow returns the row showing a line with the same type and line
+// numbers as target, or -1.
+func (d *DiffView) findRow(target git.DiffLine) int {
+	for i := range d.rows {
+		line := d.lineAt(i)
+		if line != nil && line.Type == target.Type &&
+			line.OldLineNum == target.OldLineNum && line.NewLineNum == target.NewLineNum {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *DiffView) lineAt(row int) *git.DiffLine {
+	if d.diff == nil || row < 0 || row >= len(d.rows) {
+		return nil
+	}
+	r := d.rows[row]
+	if r.hunk < 0 || r.line < 0 {
+		return nil
+	}
+	return &d.diff.Hunks[r.hunk].Lines[r.line]
+}
+
+// CurrentHunk returns the hunk containing the cursor, or nil if the cursor
+// is above the first hunk.
+func (d *DiffView) CurrentHunk() *git.Hunk {
+	if d.diff == nil || d.cursor < 0 || d.cursor >= len(d.rows) || d.rows[d.cursor].hunk < 0 {
+		return nil
+	}
+	return &d.diff.Hunks[d.rows[d.cursor].hunk]
+}
+
+// SelectedLine returns the diff line under the cursor, or nil if the cursor
+// is on a hunk header or spacer.
+func (d *DiffView) SelectedLine() *git.DiffLine {
+	return d.lineAt(d.cursor)
+}
+
+// jumpForward moves to the first start after the cursor. It returns false
+// if there is none.
+func (d *DiffView) jumpForward(starts []int) bool {
+	for _, row := range starts {
+		if row > d.cursor {
+			d.jumpTo(row)
+			return true
+		}
+	}
+	return false
+}
+
+// jumpBackward moves to the last start before the cursor. It returns false
+// if there is none.
+func (d *DiffView) jumpBackward(starts []int) bool {
+	for i := len(starts) - 1; i >= 0; i-- {
+		if starts[i] < d.cursor {
+			d.jumpTo(starts[i])
+			return true
+		}
+	}
+	return false
+}
+
+// Jump positions the cursor for a freshly loaded diff.
+func (d *DiffView) Jump(target jumpTarget) {
+	var starts []int
+	switch target {
+	case jumpFirstHunk, jumpLastHunk:
+		starts = d.hunkStarts()
+	case jumpFirstChange, jumpLastChange:
+		starts = d.changeStarts()
+	}
+	if len(starts) == 0 {
+		return
+	}
+	switch target {
+	case jumpFirstHunk, jumpFirstChange:
+		d.jumpTo(starts[0])
+	case jumpLastHunk, jumpLastChange:
+		d.jumpTo(starts[len(starts)-1])
+	}
+}
+
+func fileJump(forward bool, target jumpTarget) tea.Cmd {
+	return func() tea.Msg {
+		return fileJumpMsg{forward: forward, target: target}
+	}
+}
+
 func (d *DiffView) Update(msg tea.Msg) (*DiffView, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -157,22 +703,44 @@ func (d *DiffView) Update(msg tea.Msg) (*DiffView, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, d.keys.Up):
-			d.viewport.ScrollUp(1)
+			d.setCursor(d.cursor - 1)
 		case key.Matches(msg, d.keys.Down):
-			d.viewport.ScrollDown(1)
+			d.setCursor(d.cursor + 1)
 		case key.Matches(msg, d.keys.Top):
 			d.viewport.GotoTop()
+			d.setCursor(0)
 		case key.Matches(msg, d.keys.Bottom):
 			d.viewport.GotoBottom()
+			d.setCursor(len(d.rows) - 1)
 		case key.Matches(msg, d.keys.PageUp):
 			d.viewport.HalfViewUp()
+			d.setCursor(d.cursor - d.viewport.Height/2)
 		case key.Matches(msg, d.keys.PageDown):
 			d.viewport.HalfViewDown()
+			d.setCursor(d.cursor + d.viewport.Height/2)
+		case key.Matches(msg, d.keys.NextHunk):
+			if !d.jumpForward(d.hunkStarts()) {
+				cmd = fileJump(true, jumpFirstHunk)
+			}
+		case key.Matches(msg, d.keys.PrevHunk):
+			if !d.jumpBackward(d.hunkStarts()) {
+				cmd = fileJump(false, jumpLastHunk)
+			}
+		case key.Matches(msg, d.keys.NextChange):
+			if !d.jumpForward(d.changeStarts()) {
+				cmd = fileJump(true, jumpFirstChange)
+			}
+		case key.Matches(msg, d.keys.PrevChange):
+			if !d.jumpBackward(d.changeStarts()) {
+				cmd = fileJump(false, jumpLastChange)
+			}
 		default:
 			d.viewport, cmd = d.viewport.Update(msg)
+			d.clampCursor()
 		}
 	default:
 		d.viewport, cmd = d.viewport.Update(msg)
+		d.clampCursor()
 	}
 
 	return d, cmd
@@ -182,11 +750,23 @@ func (d *DiffView) View(active bool) string {
 	title := "No file selected"
 	if d.filePath != "" {
 		title = d.filePath
+		if d.diff != nil && d.diff.OldPath != "" {
+			title = d.diff.OldPath + " → " + d.filePath
+		}
 		if d.diff != nil && d.diff.Staged {
 			title += " (staged)"
 		}
+		if d.diff != nil && d.diff.Commit != "" {
+			title += " @ " + shortHash(d.diff.Commit)
+		}
 	}
 	titleStyled := d.styles.DiffTitle.Render(title)
+	for _, label := range fileLabels(d.diff) {
+		titleStyled += " " + d.styles.DiffFileLabel.Render(label)
+	}
+	for _, flag := range d.flags {
+		titleStyled += " " + d.styles.DiffFlag.Render("["+flag+"]")
+	}
 
 	var content string
 	if d.diff == nil {
@@ -194,11 +774,13 @@ func (d *DiffView) View(active bool) string {
 			Foreground(ColorDim).
 			Italic(true).
 			Render("Select a file to view diff")
+	} else if d.diff.Binary {
+		content = renderBinarySummary(d.diff.Summary)
 	} else if len(d.diff.Hunks) == 0 {
 		content = lipgloss.NewStyle().
 			Foreground(ColorDim).
 			Italic(true).
-			Render("No changes in this file")
+			Render(emptyDiffMessage(d.diff))
 	} else {
 		content = d.viewport.View()
 	}
@@ -216,6 +798,83 @@ func (d *DiffView) View(active bool) string {
 		Render(fullContent)
 }
 
+// fileLabels describes file-level changes shown in the title: renames,
+// copies, additions, deletions and mode changes.
+func fileLabels(diff *git.FileDiff) []string {
+	if diff == nil {
+		return nil
+	}
+
+	var labels []string
+	switch {
+	case diff.Renamed:
+		labels = append(labels, fmt.Sprintf("renamed %d%%", diff.Similarity))
+	case diff.Copied:
+		labels = append(labels, fmt.Sprintf("copied %d%%", diff.Similarity))
+	case diff.NewFile:
+		labels = append(labels, "new file")
+	case diff.Deleted:
+		labels = append(labels, "deleted")
+	}
+	if diff.Binary {
+		labels = append(labels, "binary")
+	}
+	if diff.ModeChanged() {
+		labels = append(labels, fmt.Sprintf("mode %s → %s", diff.OldMode, diff.NewMode))
+	}
+	return labels
+}
+
+// renderBinarySummary shows what is known about a binary file in place of
+// a diff.
+func renderBinarySummary(summary *git.BlobSummary) string {
+	dim := lipgloss.NewStyle().Foreground(ColorDim)
+	text := lipgloss.NewStyle().Foreground(ColorFg)
+
+	lines := []string{dim.Italic(true).Render("Binary file, not shown"), ""}
+	if summary != nil {
+		if summary.Type != "" {
+			lines = append(lines, dim.Render("Type: ")+text.Render(summary.Type))
+		}
+		size := fmt.Sprintf("%s → %s", formatSize(summary.OldSize), formatSize(summary.NewSize))
+		lines = append(lines, dim.Render("Size: ")+text.Render(size))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatSize renders a byte count for humans; negative sizes mean the file
+// does not exist on that side.
+func formatSize(size int64) string {
+	if size < 0 {
+		return "none"
+	}
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// emptyDiffMessage explains a diff without hunks, which happens when only
+// the path or mode changed.
+func emptyDiffMessage(diff *git.FileDiff) string {
+	switch {
+	case diff.Renamed:
+		return "File renamed without content changes"
+	case diff.Copied:
+		return "File copied without content changes"
+	case diff.ModeChanged():
+		return "Only the file mode changed"
+	default:
+		return "No changes in this file"
+	}
+}
+
 func (d *DiffView) ScrollPercent() float64 {
 	return d.viewport.ScrollPercent()
 }
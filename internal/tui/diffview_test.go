@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"grua/internal/checks"
+	"grua/internal/complexity"
+	"grua/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testDiff has two hunks; the first has two separate runs of changes.
+func testDiff() *git.FileDiff {
+	return &git.FileDiff{
+		Path: "a.go",
+		Hunks: []git.Hunk{
+			{
+				Header: "@@ -1,5 +1,5 @@", OldStart: 1, NewStart: 1,
+				Lines: []git.DiffLine{
+					{Content: "package a", Type: git.LineContext, OldLineNum: 1, NewLineNum: 1},
+					{Content: "var x = 1", Type: git.LineRemoved, OldLineNum: 2},
+					{Content: "var x = 2", Type: git.LineAdded, NewLineNum: 2},
+					{Content: "", Type: git.LineContext, OldLineNum: 3, NewLineNum: 3},
+					{Content: "var y = 3", Type: git.LineAdded, NewLineNum: 4},
+				},
+			},
+			{
+				Header: "@@ -20,2 +21,2 @@", OldStart: 20, NewStart: 21,
+				Lines: []git.DiffLine{
+					{Content: "func f() {}", Type: git.LineContext, OldLineNum: 20, NewLineNum: 21},
+					{Content: "func g() {}", Type: git.LineRemoved, OldLineNum: 21},
+				},
+			},
+		},
+	}
+}
+
+func newTestDiffView(diff *git.FileDiff) *DiffView {
+	d := NewDiffView(NewStyles(), DefaultKeyMap())
+	d.SetSize(80, 40)
+	d.SetDiff(diff)
+	return d
+}
+
+func keyPress(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestDiffViewChangeNavigation(t *testing.T) {
+	d := newTestDiffView(testDiff())
+
+	var got []string
+	for {
+		var cmd tea.Cmd
+		d, cmd = d.Update(keyPress("n"))
+		if cmd != nil {
+			msg, ok := cmd().(fileJumpMsg)
+			if !ok || !msg.forward || msg.target != jumpFirstChange {
+				t.Errorf("past the last change: got %#v, want a jump to the next file", cmd())
+			}
+			break
+		}
+		if len(got) > 10 {
+			t.Fatal("navigation did not reach the end")
+		}
+		got = append(got, d.SelectedLine().Content)
+	}
+	want := []string{"var x = 1", "var y = 3", "func g() {}"}
+	if len(got) != len(want) {
+		t.Fatalf("visited %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	d, _ = d.Update(keyPress("N"))
+	if line := d.SelectedLine(); line == nil || line.Content != "var y = 3" {
+		t.Errorf("previous change = %+v, want var y = 3", line)
+	}
+}
+
+func TestDiffViewHunkNavigation(t *testing.T) {
+	d := newTestDiffView(testDiff())
+
+	d, cmd := d.Update(keyPress("]"))
+	if cmd != nil {
+		t.Fatalf("first ] left the file: %#v", cmd())
+	}
+	if h := d.CurrentHunk(); h == nil || h.OldStart != 20 {
+		t.Errorf("hunk = %+v, want the second", h)
+	}
+
+	_, cmd = d.Update(keyPress("]"))
+	if msg, ok := cmd().(fileJumpMsg); !ok || !msg.forward || msg.target != jumpFirstHunk {
+		t.Errorf("past the last hunk: got %#v, want a jump to the next file", cmd())
+	}
+
+	d.Jump(jumpFirstHunk)
+	if h := d.CurrentHunk(); h == nil || h.OldStart != 1 {
+		t.Errorf("after jumpFirstHunk hunk = %+v, want the first", h)
+	}
+	_, cmd = d.Update(keyPress("["))
+	if msg, ok := cmd().(fileJumpMsg); !ok || msg.forward || msg.target != jumpLastHunk {
+		t.Errorf("before the first hunk: got %#v, want a jump to the previous file", cmd())
+	}
+
+	d.Jump(jumpLastChange)
+	if line := d.SelectedLine(); line == nil || line.Content != "func g() {}" {
+		t.Errorf("after jumpLastChange line = %+v, want func g() {}", line)
+	}
+}
+
+func TestDiffViewHunkNavigationWithPreamble(t *testing.T) {
+	d := newTestDiffView(testDiff())
+	d.SetComplexity([]complexity.Delta{{Name: "f", Line: 21, New: complexity.Func{Name: "f", Cyclomatic: 3}}})
+	d.SetFindings([]checks.Finding{
+		{Path: "a.go", Line: 0, Source: "docs", Rule: "missing-doc", Message: "file"},
+		{Path: "a.go", Line: 3, Source: "vet", Rule: "shadow", Suppressed: true, Reason: "fine"},
+	})
+	d.Jump(jumpFirstHunk)
+	if h := d.CurrentHunk(); h == nil || h.OldStart != 1 {
+		t.Fatalf("after jumpFirstHunk hunk = %+v, want the first", h)
+	}
+	first := d.cursor
+	if !strings.Contains(d.rendered[first], "@@ -1,5 +1,5 @@") {
+		t.Errorf("jumpFirstHunk landed on %q, want the first hunk's header", d.rendered[first])
+	}
+
+	d, _ = d.Update(keyPress("]"))
+	if h := d.CurrentHunk(); h == nil || h.OldStart != 20 {
+		t.Errorf("hunk = %+v, want the second", h)
+	}
+	d, _ = d.Update(keyPress("["))
+	if d.cursor != first {
+		t.Errorf("[ moved to row %d, want the first hunk's header at %d", d.cursor, first)
+	}
+	_, cmd := d.Update(keyPress("["))
+	if msg, ok := cmd().(fileJumpMsg); !ok || msg.forward {
+		t.Errorf("before the first hunk: got %#v, want a jump to the previous file", cmd())
+	}
+
+	d.setCursor(0)
+	if h := d.CurrentHunk(); h != nil {
+		t.Errorf("on the preamble CurrentHunk = %+v, want nil", h)
+	}
+	if line := d.SelectedLine(); line != nil {
+		t.Errorf("on the preamble SelectedLine = %+v, want nil", line)
+	}
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"grua/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultLineTemplate is used for editors without a configured template.
+const defaultLineTemplate = "+{line} {file}"
+
+// editorCommand returns the user's editor split into program and
+// arguments: the configured override, then $VISUAL or $EDITOR, falling
+// back to vi.
+func editorCommand(override string) []string {
+	if fields := strings.Fields(override); len(fields) > 0 {
+		return fields
+	}
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// editorLineArgs returns the full command that opens file at line, using
+// the template for the editor's program name.
+func editorLineArgs(editor []string, templates map[string]string, file string, line int) []string {
+	template, ok := templates[filepath.Base(editor[0])]
+	if !ok {
+		template = defaultLineTemplate
+	}
+
+	args := append([]string(nil), editor...)
+	for _, arg := range strings.Fields(template) {
+		arg = strings.ReplaceAll(arg, "{file}", file)
+		arg = strings.ReplaceAll(arg, "{line}", strconv.Itoa(line))
+		args = append(args, arg)
+	}
+	return args
+}
+
+// editorClosedMsg is sent when an editor opened on the current file exits.
+type editorClosedMsg struct {
+	err error
+}
+
+// editorLineMsg carries the working tree line to open a file at, once it
+// has been found.
+type editorLineMsg struct {
+	path string
+	line int
+	err  error
+}
+
+// targetLine picks the line to open for the selected diff line on the new
+// side, or on the old side if old is set. Lines missing from that side are
+// replaced by the next line that is there, or the last one before it at
+// the end of a hunk.
+func targetLine(hunk *git.Hunk, line *git.DiffLine, old bool) int {
+	if hunk == nil {
+		return 1
+	}
+	lineNum := func(l *git.DiffLine) int {
+		if old {
+			return l.OldLineNum
+		}
+		return l.NewLineNum
+	}
+	if line != nil && lineNum(line) > 0 {
+		return lineNum(line)
+	}
+
+	target, _ := hunk.NewRange()
+	if old {
+		target = hunk.OldStart
+		if oldCount, _ := hunk.Counts(); oldCount == 0 {
+			target++
+		}
+	}
+	seen := line == nil
+	for i := range hunk.Lines {
+		l := &hunk.Lines[i]
+		if l == line {
+			seen = true
+		}
+		if lineNum(l) > 0 {
+			target = lineNum(l)
+			if seen {
+				break
+			}
+		}
+	}
+	return max(target, 1)
+}
+
+// currentLine returns the line the cursor is on in the version of the
+// current file that is shown: the working tree, or for commit diffs the
+// file as of the commit.
+func (m *Model) currentLine() int {
+	switch {
+	case m.showingConflict():
+		if region := m.conflictView.selectedRegion(); region != nil {
+			return region.Start + 1
+		}
+		return 1
+	case m.showingModule():
+		// The module view lists requirements, not lines of go.mod.
+		return 1
+	case m.showsFormatDiff(*m.currentFile):
+		// The working tree is the old side of the formatting changes.
+		return targetLine(m.diffView.CurrentHunk(), m.diffView.SelectedLine(), true)
+	}
+	return targetLine(m.diffView.CurrentHunk(), m.diffView.SelectedLine(), false)
+}
+
+// openInEditor suspends the program and opens the current file at the
+// selected line.
+func (m *Model) openInEditor() tea.Cmd {
+	if m.currentFile == nil {
+		return nil
+	}
+	if m.currentFile.Deleted {
+		m.statusMsg = m.currentFile.Path + " was deleted"
+		return nil
+	}
+
+	path, line := m.currentFile.Path, m.currentLine()
+	if rev := m.currentFile.Commit; rev != "" {
+		// The line is in the file as of the commit, which may have moved
+		// since.
+		return func() tea.Msg {
+			line, err := m.gitService.WorkingTreeLine(rev, path, line)
+			return editorLineMsg{path: path, line: line, err: err}
+		}
+	}
+	return m.editAt(path, line)
+}
+
+// editAt suspends the program and opens path at line.
+func (m *Model) editAt(path string, line int) tea.Cmd {
+	file := filepath.Join(m.repoPath, path)
+	args := editorLineArgs(editorCommand(m.cfg.Editor), m.cfg.EditorTemplates, file, line)
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = m.repoPath
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorClosedMsg{err: err}
+	})
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"grua/internal/git"
+)
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got := editorCommand(""); !slices.Equal(got, []string{"vi"}) {
+		t.Errorf("no editor = %q, want vi", got)
+	}
+	t.Setenv("EDITOR", "nano")
+	if got := editorCommand(" "); !slices.Equal(got, []string{"nano"}) {
+		t.Errorf("$EDITOR = %q, want nano", got)
+	}
+	t.Setenv("VISUAL", "code --wait")
+	if got := editorCommand(""); !slices.Equal(got, []string{"code", "--wait"}) {
+		t.Errorf("$VISUAL = %q, want code --wait", got)
+	}
+	if got := editorCommand("hx"); !slices.Equal(got, []string{"hx"}) {
+		t.Errorf("override = %q, want hx", got)
+	}
+}
+
+func TestEditorLineArgs(t *testing.T) {
+	templates := map[string]string{
+		"code": "--goto {file}:{line}",
+		"hx":   "{file}:{line}",
+	}
+	tests := []struct {
+		editor []string
+		want   []string
+	}{
+		{[]string{"/usr/bin/code", "--wait"}, []string{"/usr/bin/code", "--wait", "--goto", "/r/a.go:12"}},
+		{[]string{"hx"}, []string{"hx", "/r/a.go:12"}},
+		{[]string{"ed"}, []string{"ed", "+12", "/r/a.go"}},
+	}
+	for _, tt := range tests {
+		got := editorLineArgs(tt.editor, templates, "/r/a.go", 12)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("editorLineArgs(%q) = %q, want %q", tt.editor, got, tt.want)
+		}
+	}
+}
+
+func TestTargetLine(t *testing.T) {
+	hunk := &git.Hunk{
+		OldStart: 10,
+		NewStart: 10,
+		Lines: []git.DiffLine{
+			{Type: git.LineContext, OldLineNum: 10, NewLineNum: 10},
+			{Type: git.LineRemoved, OldLineNum: 11},
+			{Type: git.LineAdded, NewLineNum: 11},
+			{Type: git.LineContext, OldLineNum: 12, NewLineNum: 12},
+			{Type: git.LineRemoved, OldLineNum: 13},
+		},
+	}
+	tests := []struct {
+		name string
+		line *git.DiffLine
+		old  bool
+		want int
+	}{
+		{"no line", nil, false, 10},
+		{"added line", &hunk.Lines[2], false, 11},
+		{"removed line", &hunk.Lines[1], false, 11},
+		{"removed at the end", &hunk.Lines[4], false, 12},
+		// Format diffs have the working tree on the old side.
+		{"old side, removed line", &hunk.Lines[1], true, 11},
+		{"old side, added line", &hunk.Lines[2], true, 12},
+		{"old side, removed at the end", &hunk.Lines[4], true, 13},
+	}
+	for _, tt := range tests {
+		if got := targetLine(hunk, tt.line, tt.old); got != tt.want {
+			t.Errorf("%s: targetLine = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if got := targetLine(nil, nil, false); got != 1 {
+		t.Errorf("no hunk: targetLine = %d, want 1", got)
+	}
+	deleted := &git.Hunk{Lines: []git.DiffLine{{Type: git.LineRemoved, OldLineNum: 1}}}
+	if got := targetLine(deleted, &deleted.Lines[0], false); got != 1 {
+		t.Errorf("emptied file: targetLine = %d, want 1", got)
+	}
+}
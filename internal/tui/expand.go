@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"grua/internal/git"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// expandStep is how many lines each expand key reveals.
+const expandStep = 10
+
+// fileKey identifies a file entry. The same path can be listed both staged
+// and unstaged, or in several commits, with different diffs.
+type fileKey struct {
+	path   string
+	staged bool
+	commit string
+}
+
+func keyOf(file git.FileStatus) fileKey {
+	return fileKey{path: file.Path, staged: file.Staged, commit: file.Commit}
+}
+
+// expansion records context revealed beyond what git diff shows, so it
+// survives the periodic reload.
+type expansion struct {
+	full   bool
+	ranges []git.LineRange
+}
+
+// apply reveals the expanded context in diff by reading the new side of
+// the file.
+func (e expansion) apply(service *git.Service, file git.FileStatus, diff *git.FileDiff) *git.FileDiff {
+	if !e.full && len(e.ranges) == 0 {
+		return diff
+	}
+	var newLines []string
+	var err error
+	if file.Commit != "" {
+		newLines, err = service.GetRevisionContent(file.Commit, file.Path)
+	} else {
+		newLines, err = service.GetNewContent(file.Path, file.Staged)
+	}
+	if err != nil {
+		return diff
+	}
+	ranges := e.ranges
+	if e.full {
+		ranges = []git.LineRange{{Start: 1, End: len(newLines)}}
+	}
+	return git.ExpandContext(diff, newLines, ranges)
+}
+
+// handleDiffOptionKey handles the keys that change how diffs are generated:
+// context size, expansion and the whitespace and move modes. It returns
+// false if msg is not one of them.
+func (m *Model) handleDiffOptionKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.IgnoreWhitespace):
+		m.diffOpts.IgnoreWhitespace = !m.diffOpts.IgnoreWhitespace
+	case key.Matches(msg, m.keys.IgnoreBlankLines):
+		m.diffOpts.IgnoreBlankLines = !m.diffOpts.IgnoreBlankLines
+	case key.Matches(msg, m.keys.DetectMoves):
+		m.diffOpts.DetectMoves = !m.diffOpts.DetectMoves
+	case key.Matches(msg, m.keys.LoadAll):
+		if m.currentFile == nil {
+			return nil, true
+		}
+		m.uncapped[keyOf(*m.currentFile)] = true
+	case key.Matches(msg, m.keys.MoreContext):
+		m.diffOpts.Context++
+	case key.Matches(msg, m.keys.LessContext):
+		if m.diffOpts.Context == 0 {
+			return nil, true
+		}
+		m.diffOpts.Context--
+	case key.Matches(msg, m.keys.ExpandUp), key.Matches(msg, m.keys.ExpandDown),
+		key.Matches(msg, m.keys.FullFile):
+		if m.currentFile == nil || m.currentFile.Unversioned {
+			return nil, true
+		}
+		k := keyOf(*m.currentFile)
+		exp := m.expansions[k]
+		if exp == nil {
+			exp = &expansion{}
+			m.expansions[k] = exp
+		}
+
+		if key.Matches(msg, m.keys.FullFile) {
+			exp.full = !exp.full
+			break
+		}
+		hunk := m.diffView.CurrentHunk()
+		if hunk == nil {
+			return nil, true
+		}
+		start, end := hunk.NewRange()
+		if key.Matches(msg, m.keys.ExpandUp) {
+			exp.ranges = append(exp.ranges, git.LineRange{Start: start - expandStep, End: start - 1})
+		} else {
+			exp.ranges = append(exp.ranges, git.LineRange{Start: end + 1, End: end + expandStep})
+		}
+	default:
+		return nil, false
+	}
+
+	m.diffView.SetFlags(m.diffFlags())
+	if m.currentFile == nil {
+		return nil, true
+	}
+	return m.loadDiff(*m.currentFile), true
+}
+
+// diffFlags describes the active diff modes for the diff view title.
+func (m *Model) diffFlags() []string {
+	opts := m.diffOpts
+	var flags []string
+	if opts.IgnoreWhitespace {
+		flags = append(flags, "ignore ws")
+	}
+	if opts.IgnoreBlankLines {
+		flags = append(flags, "ignore blank")
+	}
+	if opts.DetectMoves {
+		flags = append(flags, "moves")
+	}
+	if m.showFormat {
+		flags = append(flags, "gofmt")
+	}
+	return flags
+}
@@ -26,8 +26,25 @@ type FileList struct {
 	height int
 	styles *Styles
 	keys   KeyMap
+	// commitLabel heads the files of a commit; empty uses the hash.
+	commitLabel string
+	// marks holds notes from checks shown after the status badge, by
+	// kind and then path.
+	marks [markKinds]map[string]string
 }
 
+// markKind identifies the check a file list mark comes from. Marks are
+// shown in this order.
+type markKind int
+
+const (
+	markFindings markKind = iota
+	markFormat
+	markTests
+	markCoverage
+	markKinds
+)
+
 func NewFileList(styles *Styles, keys KeyMap) *FileList {
 	return &FileList{
 		styles: styles,
@@ -40,14 +57,42 @@ func (f *FileList) SetSize(width, height int) {
 	f.height = height
 }
 
+// SetMarks replaces the marks of one kind, keyed by file path.
+func (f *FileList) SetMarks(kind markKind, marks map[string]string) {
+	f.marks[kind] = marks
+}
+
+// fileMarks joins the marks for a path.
+func (f *FileList) fileMarks(path string) string {
+	var marks []string
+	for _, m := range f.marks {
+		if mark, ok := m[path]; ok {
+			marks = append(marks, mark)
+		}
+	}
+	return strings.Join(marks, " ")
+}
+
+// SetCommitLabel sets the header shown above files from a commit, such as
+// the ref of a stash entry. An empty label shows the commit hash.
+func (f *FileList) SetCommitLabel(label string) {
+	f.commitLabel = label
+}
+
 func (f *FileList) SetFiles(files []git.FileStatus) {
 	prevSelected := f.SelectedFile()
 
 	f.items = nil
 
-	var staged, unstaged, unversioned []git.FileStatus
+	var committed, committedUntracked, conflicts, staged, unstaged, unversioned []git.FileStatus
 	for _, file := range files {
-		if file.Unversioned {
+		if file.Commit != "" && file.Unversioned {
+			committedUntracked = append(committedUntracked, file)
+		} else if file.Commit != "" {
+			committed = append(committed, file)
+		} else if file.Unmerged {
+			conflicts = append(conflicts, file)
+		} else if file.Unversioned {
 			unversioned = append(unversioned, file)
 		} else if file.Staged {
 			staged = append(staged, file)
@@ -56,6 +101,32 @@ func (f *FileList) SetFiles(files []git.FileStatus) {
 		}
 	}
 
+	if len(committed) > 0 {
+		label := f.commitLabel
+		if label == "" {
+			label = "COMMIT " + shortHash(committed[0].Commit)
+		}
+		// The header carries the commit so it is styled as one.
+		f.items = append(f.items, FileListItem{
+			File:       git.FileStatus{Commit: committed[0].Commit},
+			IsHeader:   true,
+			HeaderText: label,
+		})
+		for _, file := range committed {
+			f.items = append(f.items, FileListItem{File: file})
+		}
+	}
+
+	if len(conflicts) > 0 {
+		f.items = append(f.items, FileListItem{
+			IsHeader:   true,
+			HeaderText: "CONFLICTS",
+		})
+		for _, file := range conflicts {
+			f.items = append(f.items, FileListItem{File: file})
+		}
+	}
+
 	if len(staged) > 0 {
 		f.items = append(f.items, FileListItem{
 			IsHeader:   true,
@@ -76,6 +147,8 @@ func (f *FileList) SetFiles(files []git.FileStatus) {
 		}
 	}
 
+	// Untracked files saved in a stash are listed like unversioned ones.
+	unversioned = append(committedUntracked, unversioned...)
 	if len(unversioned) > 0 {
 		f.items = append(f.items, FileListItem{
 			IsHeader:   true,
@@ -95,6 +168,14 @@ func (f *FileList) SetFiles(files []git.FileStatus) {
 				return
 			}
 		}
+		// A file that moved between sections, e.g. a conflict that was
+		// resolved and staged, keeps the selection.
+		for i, item := range f.items {
+			if !item.IsHeader && item.File.Path == prevSelected.Path {
+				f.cursor = i
+				return
+			}
+		}
 	}
 
 	f.cursor = f.firstFileIndex()
@@ -155,6 +236,16 @@ func (f *FileList) moveDown() {
 	}
 }
 
+// SelectNext moves the cursor to the next file, wrapping at the end.
+func (f *FileList) SelectNext() {
+	f.moveDown()
+}
+
+// SelectPrev moves the cursor to the previous file, wrapping at the start.
+func (f *FileList) SelectPrev() {
+	f.moveUp()
+}
+
 func (f *FileList) firstFileIndex() int {
 	for i, item := range f.items {
 		if !item.IsHeader {
@@ -185,15 +276,24 @@ func (f *FileList) SelectedFile() *git.FileStatus {
 }
 
 func (f *FileList) View(active bool) string {
+	borderStyle := f.styles.FileListBorder
+	if active {
+		borderStyle = f.styles.FileListBorderActive
+	}
+
 	if len(f.items) == 0 {
 		emptyMsg := lipgloss.NewStyle().
 			Foreground(ColorDim).
 			Italic(true).
 			Render("No changes")
-		return emptyMsg
+		return borderStyle.
+			Width(f.width).
+			Height(f.height).
+			Render(emptyMsg)
 	}
 
 	var lines []string
+	cursorRow := 0
 	for i, item := range f.items {
 		var line string
 		isSelected := i == f.cursor && !item.IsHeader
@@ -201,22 +301,34 @@ func (f *FileList) View(active bool) string {
 		if item.IsHeader {
 			headerStyle := f.styles.StagedHeader
 			switch item.HeaderText {
+			case "CONFLICTS":
+				headerStyle = f.styles.ConflictHeader
 			case "UNSTAGED":
 				headerStyle = f.styles.UnstagedHeader
 			case "UNVERSIONED":
 				headerStyle = f.styles.UnversionedHeader
 			}
+			if item.File.Commit != "" {
+				headerStyle = f.styles.CommitHeader
+			}
 			line = headerStyle.Render(fmt.Sprintf(" ▾ %s", item.HeaderText))
 		} else {
-			filename := filepath.Base(item.File.Path)
-			status := item.File.Status
+			filename := displayName(item.File)
+			status := statusBadge(item.File)
+			var marks string
+			if item.File.Commit == "" {
+				marks = f.fileMarks(item.File.Path)
+			}
 
-			maxNameLen := f.width - 8
+			maxNameLen := f.width - 7 - lipgloss.Width(status)
+			if marks != "" {
+				maxNameLen -= lipgloss.Width(marks) + 1
+			}
 			if maxNameLen < 10 {
 				maxNameLen = 10
 			}
-			if len(filename) > maxNameLen {
-				filename = filename[:maxNameLen-3] + "..."
+			if runes := []rune(filename); len(runes) > maxNameLen {
+				filename = string(runes[:maxNameLen-3]) + "..."
 			}
 
 			paddedName := fmt.Sprintf("%-*s", maxNameLen, filename)
@@ -224,29 +336,88 @@ func (f *FileList) View(active bool) string {
 			if isSelected {
 				line = f.styles.FileItemSelected.
 					Width(f.width - 4).
-					Render(fmt.Sprintf("%s %s", paddedName, status))
+					Render(strings.TrimSpace(fmt.Sprintf("%s %s %s", paddedName, status, marks)))
 			} else {
-				line = f.styles.FileItem.Render(paddedName) +
+				nameStyle := f.styles.FileItem
+				if item.File.Deleted {
+					nameStyle = f.styles.FileItemDeleted
+				}
+				line = nameStyle.Render(paddedName) +
 					f.styles.StatusBadge.Render(status)
+				if marks != "" {
+					line += " " + f.styles.FileMark.Render(marks)
+				}
 			}
 		}
 
-		lines = append(lines, line)
+		if i == f.cursor {
+			cursorRow = len(lines)
+		}
+		// Headers with a top margin render as more than one row.
+		lines = append(lines, strings.Split(line, "\n")...)
 	}
 
-	content := strings.Join(lines, "\n")
-
-	borderStyle := f.styles.FileListBorder
-	if active {
-		borderStyle = f.styles.FileListBorderActive
+	// Keep the cursor in view when the list is taller than the pane.
+	if f.height > 0 && len(lines) > f.height {
+		start := max(min(cursorRow-f.height/2, len(lines)-f.height), 0)
+		lines = lines[start : start+f.height]
 	}
 
+	content := strings.Join(lines, "\n")
+
 	return borderStyle.
 		Width(f.width).
 		Height(f.height).
 		Render(content)
 }
 
+// shortHash abbreviates a commit hash for display.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// displayName is the name shown for a file. Renames and copies show the
+// source name too when it differs.
+func displayName(file git.FileStatus) string {
+	name := filepath.Base(file.Path)
+	if file.OldPath != "" {
+		if oldName := filepath.Base(file.OldPath); oldName != name {
+			name = oldName + " → " + name
+		}
+	}
+	return name
+}
+
+// statusBadge is the short status shown after a file name: the status
+// letter, the similarity of a rename or copy, +x/-x for executable bit
+// changes, and a note for binary or large files.
+func statusBadge(file git.FileStatus) string {
+	badge := file.Status
+	if file.Similarity > 0 && file.Similarity < 100 {
+		badge += fmt.Sprintf("%d", file.Similarity)
+	}
+	if file.OldMode != "" && file.NewMode != "" {
+		switch {
+		case file.NewMode == "100755":
+			badge += "+x"
+		case file.OldMode == "100755":
+			badge += "-x"
+		default:
+			badge += "*"
+		}
+	}
+	switch {
+	case file.Binary:
+		badge += " bin"
+	case file.Large:
+		badge += " big"
+	}
+	return badge
+}
+
 func (f *FileList) Cursor() int {
 	return f.cursor
 }
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"grua/internal/git"
+	"grua/internal/gofmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// formatMsg lists the changed files whose formatting differs from gofmt's.
+type formatMsg struct {
+	unformatted map[string]bool
+}
+
+type formatAppliedMsg struct {
+	path    string
+	changed bool
+	err     error
+}
+
+// hasWorktreeVersion reports whether a file has a working tree version
+// that checks can run on: deleted files have none and conflicted files are
+// not valid Go.
+func hasWorktreeVersion(file git.FileStatus) bool {
+	return file.Commit == "" && !file.Deleted && !file.Unmerged && file.Submodule == ""
+}
+
+// checkFormat runs gofmt over the working tree version of each changed Go
+// file. Files that do not parse are left to the compiler checks.
+func (m *Model) checkFormat(files []git.FileStatus) tea.Cmd {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if strings.HasSuffix(file.Path, ".go") && hasWorktreeVersion(file) && !seen[file.Path] {
+			seen[file.Path] = true
+			paths = append(paths, file.Path)
+		}
+	}
+
+	return func() tea.Msg {
+		unformatted := make(map[string]bool)
+		for _, path := range paths {
+			if _, changed, err := gofmt.Check(filepath.Join(m.repoPath, path)); err == nil && changed {
+				unformatted[path] = true
+			}
+		}
+		return formatMsg{unformatted: unformatted}
+	}
+}
+
+// showsFormatDiff reports whether the diff for file is replaced by the
+// changes gofmt would make.
+func (m *Model) showsFormatDiff(file git.FileStatus) bool {
+	return m.showFormat && m.unformatted[file.Path] && hasWorktreeVersion(file)
+}
+
+// loadFormatDiff diffs a file's working tree version against its
+// formatted version.
+func (m *Model) loadFormatDiff(file git.FileStatus, opts git.DiffOptions) (*git.FileDiff, error) {
+	formatted, _, err := gofmt.Check(filepath.Join(m.repoPath, file.Path))
+	if err != nil {
+		return nil, err
+	}
+	return m.gitService.GetContentDiff(file.Path, formatted, opts)
+}
+
+// toggleFormatDiff switches between the git diff and the formatting
+// changes for unformatted files.
+func (m *Model) toggleFormatDiff() tea.Cmd {
+	m.showFormat = !m.showFormat
+	m.diffView.SetFlags(m.diffFlags())
+	if m.currentFile == nil {
+		return nil
+	}
+	if m.showFormat && !m.unformatted[m.currentFile.Path] {
+		m.statusMsg = m.currentFile.Path + " is formatted"
+	}
+	return m.loadDiff(*m.currentFile)
+}
+
+// applyFormat formats the current file in place. The first press shows the
+// changes gofmt would make and asks for a second press to write them.
+func (m *Model) applyFormat() tea.Cmd {
+	if m.currentFile == nil || !hasWorktreeVersion(*m.currentFile) {
+		return nil
+	}
+	path := m.currentFile.Path
+	if !m.unformatted[path] {
+		m.statusMsg = path + " is formatted"
+		return nil
+	}
+	if m.confirmFormat != path {
+		m.confirmFormat = path
+		m.statusMsg = "Press " + m.keys.ApplyFormat.Help().Key + " again to write these changes to " + path
+		if m.showFormat {
+			return nil
+		}
+		m.showFormat = true
+		m.diffView.SetFlags(m.diffFlags())
+		return m.loadDiff(*m.currentFile)
+	}
+
+	m.confirmFormat = ""
+	return func() tea.Msg {
+		changed, err := gofmt.Apply(filepath.Join(m.repoPath, path))
+		return formatAppliedMsg{path: path, changed: changed, err: err}
+	}
+}
+
+// setFormatMarks flags unformatted files in the file list.
+func (m *Model) setFormatMarks() {
+	marks := make(map[string]string, len(m.unformatted))
+	for path := range m.unformatted {
+		marks[path] = "fmt"
+	}
+	m.fileList.SetMarks(markFormat, marks)
+}
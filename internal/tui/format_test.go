@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"grua/internal/config"
+	"grua/internal/git"
+)
+
+func TestApplyFormatConfirms(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "p.go")
+	const src = "package p\nvar x=1\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(dir, config.Config{})
+	m.currentFile = &git.FileStatus{Path: "p.go", Status: "M"}
+	m.unformatted["p.go"] = true
+
+	// The first press shows the changes, and another key cancels.
+	m.Update(keyPress("W"))
+	if !m.showFormat || m.confirmFormat != "p.go" {
+		t.Fatalf("first press: showFormat = %v, confirmFormat = %q", m.showFormat, m.confirmFormat)
+	}
+	m.Update(keyPress("j"))
+	if m.confirmFormat != "" {
+		t.Errorf("after another key: confirmFormat = %q, want it cleared", m.confirmFormat)
+	}
+	m.Update(keyPress("W"))
+	if content, _ := os.ReadFile(path); string(content) != src {
+		t.Fatalf("file changed before confirming: %q", content)
+	}
+
+	_, cmd := m.Update(keyPress("W"))
+	if cmd == nil {
+		t.Fatal("second press: got no command")
+	}
+	if msg, ok := cmd().(formatAppliedMsg); !ok || !msg.changed || msg.err != nil {
+		t.Errorf("second press: got %#v, want the file formatted", msg)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "package p\n\nvar x = 1\n" {
+		t.Errorf("file = %q, want it formatted", content)
+	}
+}
@@ -0,0 +1,312 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"grua/internal/git"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyLimit is how many commits the history browser loads.
+const historyLimit = 200
+
+// CommitList lists recent commits for the history browser, or the stash
+// entries for the stash browser. Each entry takes two rows: hash and
+// subject, then author and date.
+type CommitList struct {
+	commits []git.Commit
+	// path is the file the history is limited to, empty for the whole
+	// repository.
+	path    string
+	stashes bool
+	cursor  int
+	offset  int
+	width   int
+	height  int
+	styles  *Styles
+	keys    KeyMap
+}
+
+func NewCommitList(styles *Styles, keys KeyMap) *CommitList {
+	return &CommitList{
+		styles: styles,
+		keys:   keys,
+	}
+}
+
+func (c *CommitList) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+	c.scrollToCursor()
+}
+
+// SetCommits replaces the list, keeping the selected commit if it is still
+// listed.
+func (c *CommitList) SetCommits(commits []git.Commit, path string) {
+	c.setEntries(commits)
+	c.path = path
+	c.stashes = false
+}
+
+// SetStashes replaces the list with stash entries.
+func (c *CommitList) SetStashes(stashes []git.Commit) {
+	c.setEntries(stashes)
+	c.path = ""
+	c.stashes = true
+}
+
+func (c *CommitList) setEntries(commits []git.Commit) {
+	prev := c.SelectedCommit()
+	c.commits = commits
+	c.cursor = 0
+	if prev != nil {
+		for i, commit := range commits {
+			if commit.Hash == prev.Hash {
+				c.cursor = i
+				break
+			}
+		}
+	}
+	c.scrollToCursor()
+}
+
+// Path returns the file the history is limited to.
+func (c *CommitList) Path() string {
+	return c.path
+}
+
+// Stashes reports whether the list shows stash entries.
+func (c *CommitList) Stashes() bool {
+	return c.stashes
+}
+
+func (c *CommitList) SelectedCommit() *git.Commit {
+	if c.cursor < 0 || c.cursor >= len(c.commits) {
+		return nil
+	}
+	return &c.commits[c.cursor]
+}
+
+// visibleCommits is how many commits fit below the title.
+func (c *CommitList) visibleCommits() int {
+	return max((c.height-1)/2, 1)
+}
+
+func (c *CommitList) scrollToCursor() {
+	visible := c.visibleCommits()
+	if c.cursor < c.offset {
+		c.offset = c.cursor
+	}
+	if c.cursor >= c.offset+visible {
+		c.offset = c.cursor - visible + 1
+	}
+	c.offset = max(min(c.offset, len(c.commits)-visible), 0)
+}
+
+func (c *CommitList) moveTo(i int) {
+	if len(c.commits) == 0 {
+		return
+	}
+	c.cursor = max(min(i, len(c.commits)-1), 0)
+	c.scrollToCursor()
+}
+
+func (c *CommitList) Update(msg tea.Msg) (*CommitList, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, c.keys.Up):
+			c.moveTo(c.cursor - 1)
+		case key.Matches(msg, c.keys.Down):
+			c.moveTo(c.cursor + 1)
+		case key.Matches(msg, c.keys.Top):
+			c.moveTo(0)
+		case key.Matches(msg, c.keys.Bottom):
+			c.moveTo(len(c.commits) - 1)
+		case key.Matches(msg, c.keys.PageUp):
+			c.moveTo(c.cursor - c.visibleCommits()/2)
+		case key.Matches(msg, c.keys.PageDown):
+			c.moveTo(c.cursor + c.visibleCommits()/2)
+		}
+	}
+	return c, nil
+}
+
+func (c *CommitList) View(active bool) string {
+	borderStyle := c.styles.FileListBorder
+	if active {
+		borderStyle = c.styles.FileListBorderActive
+	}
+	innerWidth := max(c.width-4, 1)
+
+	title, empty := "HISTORY", "No commits"
+	if c.path != "" {
+		title += " " + c.path
+	}
+	if c.stashes {
+		title, empty = "STASHES", "No stashes"
+	}
+	lines := []string{c.styles.CommitHeader.Render(truncate(" ▾ "+title, innerWidth))}
+
+	if len(c.commits) == 0 {
+		lines = append(lines, c.styles.CommitMeta.Italic(true).Render("  "+empty))
+	}
+
+	end := min(c.offset+c.visibleCommits(), len(c.commits))
+	for i := c.offset; i < end; i++ {
+		commit := c.commits[i]
+		subject := truncate(commit.Subject, innerWidth-len(commit.Short)-3)
+		meta := truncate(fmt.Sprintf("%s · %s", commit.Author, commit.Date.Format("2006-01-02")), innerWidth-2)
+
+		if i == c.cursor {
+			lines = append(lines,
+				c.styles.FileItemSelected.Width(innerWidth).Render(commit.Short+" "+subject),
+				c.styles.FileItemSelected.Width(innerWidth).Render(meta))
+			continue
+		}
+		lines = append(lines,
+			"  "+c.styles.CommitHash.Render(commit.Short)+" "+c.styles.FileItem.UnsetPaddingLeft().Render(subject),
+			"  "+c.styles.CommitMeta.Render(meta))
+	}
+
+	return borderStyle.
+		Width(c.width).
+		Height(c.height).
+		Render(strings.Join(lines, "\n"))
+}
+
+type logMsg struct {
+	path    string
+	commits []git.Commit
+	err     error
+}
+
+type commitFilesMsg struct {
+	hash  string
+	files []git.FileStatus
+	err   error
+}
+
+func (m *Model) loadLog(path string) tea.Cmd {
+	return func() tea.Msg {
+		commits, err := m.gitService.GetLog(path, historyLimit)
+		return logMsg{path: path, commits: commits, err: err}
+	}
+}
+
+func (m *Model) loadCommitFiles(hash string) tea.Cmd {
+	return func() tea.Msg {
+		files, err := m.gitService.GetCommitFiles(hash)
+		return commitFilesMsg{hash: hash, files: files, err: err}
+	}
+}
+
+type stashesMsg struct {
+	stashes []git.Commit
+	err     error
+}
+
+// stashOpMsg reports the result of applying, popping or dropping a stash.
+type stashOpMsg struct {
+	op     string
+	ref    string
+	output string
+	err    error
+}
+
+func (m *Model) loadStashes() tea.Msg {
+	stashes, err := m.gitService.GetStashes()
+	return stashesMsg{stashes: stashes, err: err}
+}
+
+func (m *Model) loadStashFiles(hash string) tea.Cmd {
+	return func() tea.Msg {
+		files, err := m.gitService.GetStashFiles(hash)
+		return commitFilesMsg{hash: hash, files: files, err: err}
+	}
+}
+
+// openStashes switches to the stash browser.
+func (m *Model) openStashes() tea.Cmd {
+	m.showHistory = true
+	m.activePane = PaneCommitList
+	m.updateLayout()
+	return m.loadStashes
+}
+
+// handleStashKey applies, pops or drops the selected stash entry. Dropping
+// asks for the key to be pressed twice. It returns false if msg is not a
+// stash key.
+func (m *Model) handleStashKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	stash := m.commitList.SelectedCommit()
+	if !m.commitList.Stashes() || stash == nil {
+		return nil, false
+	}
+
+	var op string
+	var run func(string) (string, error)
+	switch {
+	case key.Matches(msg, m.keys.StashApply):
+		op, run = "applied", m.gitService.ApplyStash
+	case key.Matches(msg, m.keys.StashPop):
+		op, run = "popped", m.gitService.PopStash
+	case key.Matches(msg, m.keys.StashDrop):
+		if m.confirmDrop != stash.Hash {
+			m.confirmDrop = stash.Hash
+			m.statusMsg = "Press " + m.keys.StashDrop.Help().Key + " again to drop " + stash.Short
+			return nil, true
+		}
+		op, run = "dropped", m.gitService.DropStash
+	default:
+		return nil, false
+	}
+
+	m.confirmDrop = ""
+	ref := stash.Short
+	return func() tea.Msg {
+		output, err := run(ref)
+		return stashOpMsg{op: op, ref: ref, output: output, err: err}
+	}, true
+}
+
+// openHistory switches to the history browser, for the whole repository
+// or, if path is set, for one file.
+func (m *Model) openHistory(path string) tea.Cmd {
+	m.showHistory = true
+	m.activePane = PaneCommitList
+	m.updateLayout()
+	return m.loadLog(path)
+}
+
+// closeHistory returns to the working tree changes.
+func (m *Model) closeHistory() tea.Cmd {
+	m.showHistory = false
+	m.activePane = PaneFileList
+	m.currentFile = nil
+	m.fileList.SetFiles(m.files)
+	m.updateLayout()
+	if file := m.fileList.SelectedFile(); file != nil {
+		m.currentFile = file
+		return m.loadDiff(*file)
+	}
+	m.diffView.SetDiff(nil)
+	return nil
+}
+
+// selectCommit shows the files of the selected commit or stash entry.
+func (m *Model) selectCommit() tea.Cmd {
+	commit := m.commitList.SelectedCommit()
+	if commit == nil {
+		m.currentFile = nil
+		m.fileList.SetFiles(nil)
+		m.diffView.SetDiff(nil)
+		return nil
+	}
+	if m.commitList.Stashes() {
+		return m.loadStashFiles(commit.Hash)
+	}
+	return m.loadCommitFiles(commit.Hash)
+}
@@ -4,15 +4,51 @@ import "github.com/charmbracelet/bubbles/key"
 
 // KeyMap defines the key bindings for the application.
 type KeyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Top      key.Binding
-	Bottom   key.Binding
-	PageUp   key.Binding
-	PageDown key.Binding
-	Tab      key.Binding
-	Help     key.Binding
-	Quit     key.Binding
+	Up               key.Binding
+	Down             key.Binding
+	Top              key.Binding
+	Bottom           key.Binding
+	PageUp           key.Binding
+	PageDown         key.Binding
+	Tab              key.Binding
+	NextHunk         key.Binding
+	PrevHunk         key.Binding
+	NextChange       key.Binding
+	PrevChange       key.Binding
+	ExpandUp         key.Binding
+	ExpandDown       key.Binding
+	FullFile         key.Binding
+	MoreContext      key.Binding
+	LessContext      key.Binding
+	IgnoreWhitespace key.Binding
+	IgnoreBlankLines key.Binding
+	DetectMoves      key.Binding
+	LoadAll          key.Binding
+	TakeOurs         key.Binding
+	TakeTheirs       key.Binding
+	TakeBoth         key.Binding
+	MarkResolved     key.Binding
+	OpenEditor       key.Binding
+	FormatDiff       key.Binding
+	ApplyFormat      key.Binding
+	Blame            key.Binding
+	BlameInfo        key.Binding
+	RunTests         key.Binding
+	TestResults      key.Binding
+	Suppressed       key.Binding
+	History          key.Binding
+	FileHistory      key.Binding
+	Stashes          key.Binding
+	StashApply       key.Binding
+	StashPop         key.Binding
+	StashDrop        key.Binding
+	Commit           key.Binding
+	CommitSubmit     key.Binding
+	CommitAmend      key.Binding
+	CommitEditor     key.Binding
+	Cancel           key.Binding
+	Help             key.Binding
+	Quit             key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -45,6 +81,150 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("tab"),
 			key.WithHelp("Tab", "switch pane"),
 		),
+		NextHunk: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next hunk"),
+		),
+		PrevHunk: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "prev hunk"),
+		),
+		NextChange: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next change"),
+		),
+		PrevChange: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "prev change"),
+		),
+		ExpandUp: key.NewBinding(
+			key.WithKeys("{"),
+			key.WithHelp("{", "expand above"),
+		),
+		ExpandDown: key.NewBinding(
+			key.WithKeys("}"),
+			key.WithHelp("}", "expand below"),
+		),
+		FullFile: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "full file"),
+		),
+		MoreContext: key.NewBinding(
+			key.WithKeys("+", "="),
+			key.WithHelp("+", "more context"),
+		),
+		LessContext: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "less context"),
+		),
+		IgnoreWhitespace: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "ignore whitespace"),
+		),
+		IgnoreBlankLines: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "ignore blank lines"),
+		),
+		DetectMoves: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "detect moves"),
+		),
+		LoadAll: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "load full diff"),
+		),
+		TakeOurs: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "take ours"),
+		),
+		TakeTheirs: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "take theirs"),
+		),
+		TakeBoth: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "take both"),
+		),
+		MarkResolved: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "mark resolved"),
+		),
+		OpenEditor: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "open in editor"),
+		),
+		FormatDiff: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "gofmt diff"),
+		),
+		ApplyFormat: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "apply gofmt"),
+		),
+		Blame: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "blame"),
+		),
+		RunTests: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "run affected tests"),
+		),
+		TestResults: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "test results"),
+		),
+		Suppressed: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "suppressed findings"),
+		),
+		BlameInfo: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "line commit"),
+		),
+		History: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "history"),
+		),
+		FileHistory: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "file history"),
+		),
+		Stashes: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "stashes"),
+		),
+		StashApply: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "apply stash"),
+		),
+		StashPop: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pop stash"),
+		),
+		StashDrop: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "drop stash"),
+		),
+		Commit: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "commit"),
+		),
+		CommitSubmit: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("^s", "commit"),
+		),
+		CommitAmend: key.NewBinding(
+			key.WithKeys("alt+a"),
+			key.WithHelp("alt+a", "amend"),
+		),
+		CommitEditor: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("^o", "$EDITOR"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
@@ -64,6 +244,14 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Top, k.Bottom},
 		{k.PageUp, k.PageDown, k.Tab},
+		{k.NextHunk, k.PrevHunk, k.NextChange, k.PrevChange},
+		{k.ExpandUp, k.ExpandDown, k.FullFile, k.MoreContext, k.LessContext},
+		{k.IgnoreWhitespace, k.IgnoreBlankLines, k.DetectMoves, k.LoadAll},
+		{k.TakeOurs, k.TakeTheirs, k.TakeBoth, k.MarkResolved},
+		{k.OpenEditor, k.FormatDiff, k.ApplyFormat, k.Blame, k.BlameInfo},
+		{k.RunTests, k.TestResults, k.Suppressed},
+		{k.History, k.FileHistory, k.Commit},
+		{k.Stashes, k.StashApply, k.StashPop, k.StashDrop},
 		{k.Help, k.Quit},
 	}
 }
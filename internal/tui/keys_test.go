@@ -0,0 +1,19 @@
+package tui
+
+import "testing"
+
+func TestKeyMapUnique(t *testing.T) {
+	// A key bound twice does something different depending on the pane,
+	// which is easy to trigger by mistake for actions that change files.
+	seen := make(map[string]string)
+	for _, group := range DefaultKeyMap().FullHelp() {
+		for _, binding := range group {
+			for _, k := range binding.Keys() {
+				if other, ok := seen[k]; ok {
+					t.Errorf("%q is bound to both %q and %q", k, other, binding.Help().Desc)
+				}
+				seen[k] = binding.Help().Desc
+			}
+		}
+	}
+}
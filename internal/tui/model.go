@@ -1,11 +1,23 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"grua/internal/checks"
+	"grua/internal/complexity"
+	"grua/internal/config"
 	"grua/internal/git"
+	"grua/internal/gomod"
+	"grua/internal/gotest"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,24 +31,70 @@ type Pane int
 const (
 	PaneFileList Pane = iota
 	PaneDiffView
+	PaneCommitList
+	PaneTestResults
 )
 
 // Model is the main TUI model.
 type Model struct {
-	gitService *git.Service
-	fileList   *FileList
-	diffView   *DiffView
-	styles     *Styles
-	keys       KeyMap
+	gitService   *git.Service
+	repoPath     string
+	cfg          config.Config
+	fileList     *FileList
+	diffView     *DiffView
+	conflictView *ConflictView
+	moduleView   *ModuleView
+	commitDialog *CommitDialog
+	commitList   *CommitList
+	testResults  *TestResults
+	styles       *Styles
+	keys         KeyMap
 
 	activePane  Pane
 	showHelp    bool
-	width       int
-	height      int
-	ready       bool
-	files       []git.FileStatus
-	currentFile *git.FileStatus
-	err         error
+	showCommit  bool
+	showHistory bool
+	// confirmDrop is the stash entry awaiting a second press of the drop
+	// key.
+	confirmDrop string
+	showBlame   bool
+	// blameFor is the file whose blame is shown or loading.
+	blameFor    fileKey
+	blameInfo   *blameInfoMsg
+	showFormat  bool
+	unformatted map[string]bool
+	// confirmFormat is the file awaiting a second press of the format key.
+	confirmFormat string
+	// checking is set while checks run in the background; lastCheck
+	// identifies the files they ran on.
+	checking  bool
+	lastCheck string
+	findings  []checks.Finding
+	// complexity holds the complexity deltas of touched functions by
+	// file, from the same run.
+	complexity map[string][]complexity.Delta
+	showTests  bool
+	// testFiles maps changed files to their packages in the last test
+	// run, and cancelTests stops a run in progress.
+	testFiles   map[string]string
+	cancelTests context.CancelFunc
+	// coverage holds the line coverage of changed files from the last
+	// test run, and coveragePercent the share of their added statement
+	// lines covered. testedSig identifies the files that were tested.
+	coverage        map[string]gotest.Coverage
+	coveragePercent map[string]int
+	testedSig       string
+	width           int
+	height          int
+	ready           bool
+	files           []git.FileStatus
+	currentFile     *git.FileStatus
+	pendingJump     jumpTarget
+	diffOpts        git.DiffOptions
+	expansions      map[fileKey]*expansion
+	uncapped        map[fileKey]bool
+	statusMsg       string
+	err             error
 }
 
 type filesMsg struct {
@@ -49,19 +107,47 @@ type diffMsg struct {
 	err  error
 }
 
+type conflictMsg struct {
+	conflict *git.Conflict
+	err      error
+}
+
+type resolvedMsg struct {
+	path   string
+	staged bool
+	err    error
+}
+
 type tickMsg time.Time
 
-func NewModel(repoPath string) *Model {
+func NewModel(repoPath string, cfg config.Config) *Model {
 	styles := NewStyles()
 	keys := DefaultKeyMap()
 
+	diffOpts := git.DefaultDiffOptions()
+	diffOpts.Context = cfg.ContextLines
+
+	commitDialog := NewCommitDialog(styles, keys)
+	commitDialog.editor = cfg.Editor
+
 	return &Model{
-		gitService: git.NewService(repoPath),
-		fileList:   NewFileList(styles, keys),
-		diffView:   NewDiffView(styles, keys),
-		styles:     styles,
-		keys:       keys,
-		activePane: PaneFileList,
+		gitService:   git.NewService(repoPath),
+		repoPath:     repoPath,
+		cfg:          cfg,
+		fileList:     NewFileList(styles, keys),
+		diffView:     NewDiffView(styles, keys),
+		conflictView: NewConflictView(styles, keys),
+		moduleView:   NewModuleView(styles, keys),
+		commitDialog: commitDialog,
+		commitList:   NewCommitList(styles, keys),
+		testResults:  NewTestResults(styles, keys),
+		styles:       styles,
+		keys:         keys,
+		activePane:   PaneFileList,
+		diffOpts:     diffOpts,
+		expansions:   make(map[fileKey]*expansion),
+		uncapped:     make(map[fileKey]bool),
+		unformatted:  make(map[string]bool),
 	}
 }
 
@@ -81,39 +167,277 @@ func (m *Model) loadFiles() tea.Msg {
 }
 
 func (m *Model) loadDiff(file git.FileStatus) tea.Cmd {
+	if file.Unmerged {
+		return m.loadConflict(file)
+	}
+	if isModuleFile(file) {
+		return m.loadModule(file)
+	}
+
+	opts := m.diffOpts
+	if m.uncapped[keyOf(file)] {
+		opts.MaxLines = 0
+	}
+	var exp expansion
+	if e := m.expansions[keyOf(file)]; e != nil && (!file.Unversioned || file.Commit != "") {
+		exp = expansion{full: e.full, ranges: append([]git.LineRange(nil), e.ranges...)}
+	}
+	// Read the model here rather than in the command, which runs on
+	// another goroutine while Update goes on changing it.
+	formatDiff := m.showsFormatDiff(file)
+
 	return func() tea.Msg {
 		var diff *git.FileDiff
 		var err error
-		if file.Unversioned {
-			diff, err = m.gitService.GetUnversionedDiff(file.Path)
-		} else {
-			diff, err = m.gitService.GetDiff(file.Path, file.Staged)
+		switch {
+		case formatDiff:
+			diff, err = m.loadFormatDiff(file, opts)
+		case file.Commit != "":
+			diff, err = m.gitService.GetCommitDiff(file, opts)
+			if err == nil {
+				diff = exp.apply(m.gitService, file, diff)
+			}
+		case file.Unversioned:
+			diff, err = m.gitService.GetUnversionedDiff(file.Path, opts)
+		default:
+			diff, err = m.gitService.GetDiff(file, opts)
+			if err == nil {
+				diff = exp.apply(m.gitService, file, diff)
+			}
 		}
 		return diffMsg{diff: diff, err: err}
 	}
 }
 
+func (m *Model) loadConflict(file git.FileStatus) tea.Cmd {
+	return func() tea.Msg {
+		conflict, err := m.gitService.GetConflict(file)
+		return conflictMsg{conflict: conflict, err: err}
+	}
+}
+
+// loadModule compares the two sides of a go.mod change, and of the go.sum
+// beside it, as its diff would: HEAD with the index for staged changes,
+// and the index with the working tree otherwise.
+func (m *Model) loadModule(file git.FileStatus) tea.Cmd {
+	if m.moduleView.path != file.Path {
+		m.moduleView.Clear()
+	}
+	return func() tea.Msg {
+		oldRev, newRev := ":", ""
+		if file.Staged {
+			oldRev, newRev = "HEAD:", ":"
+		}
+		sumPath := path.Join(path.Dir(file.Path), "go.sum")
+		var versions [4][]byte
+		for i, v := range []struct{ rev, path string }{
+			{oldRev, file.Path}, {newRev, file.Path}, {oldRev, sumPath}, {newRev, sumPath},
+		} {
+			var err error
+			if versions[i], err = m.fileVersion(v.rev, v.path); err != nil {
+				return moduleMsg{path: file.Path, err: err}
+			}
+		}
+		diff, err := gomod.Compare(versions[0], versions[1], versions[2], versions[3])
+		return moduleMsg{path: file.Path, diff: diff, err: err}
+	}
+}
+
+// fileVersion returns a file as of rev, "HEAD:" or ":" for the index, or
+// from the working tree if rev is empty. It is nil if the file does not
+// exist there.
+func (m *Model) fileVersion(rev, p string) ([]byte, error) {
+	if rev == "" {
+		src, err := os.ReadFile(filepath.Join(m.repoPath, p))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return src, err
+	}
+	lines, err := m.gitService.GetRevisionContent(strings.TrimSuffix(rev, ":"), p)
+	if err != nil {
+		// git show fails the same way for files missing at rev.
+		return nil, nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+func (m *Model) resolveConflict(msg resolveMsg) tea.Cmd {
+	return func() tea.Msg {
+		staged, err := m.gitService.ResolveConflict(msg.path, msg.region, msg.side)
+		return resolvedMsg{path: msg.path, staged: staged, err: err}
+	}
+}
+
+// resolveDeletion keeps the working tree file of a modify/delete conflict
+// by staging it, or removes it from the index and the working tree.
+func (m *Model) resolveDeletion(msg resolveDeletionMsg) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if msg.keep {
+			err = m.gitService.StageFile(msg.path)
+		} else {
+			err = m.gitService.RemoveFile(msg.path)
+		}
+		return resolvedMsg{path: msg.path, staged: err == nil, err: err}
+	}
+}
+
+func (m *Model) markResolved(path string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.gitService.StageFile(path)
+		return resolvedMsg{path: path, staged: err == nil, err: err}
+	}
+}
+
+func (m *Model) loadStagedFiles() tea.Msg {
+	files, err := m.gitService.GetStagedFiles()
+	return stagedFilesMsg{files: files, err: err}
+}
+
+func (m *Model) loadHeadMessage() tea.Msg {
+	message, _ := m.gitService.HeadMessage()
+	return headMessageMsg{message: message}
+}
+
+func (m *Model) commit(msg commitRequestMsg) tea.Cmd {
+	return func() tea.Msg {
+		output, err := m.gitService.Commit(msg.message, msg.amend)
+		return commitDoneMsg{output: output, err: err}
+	}
+}
+
+// updateCommitDialog routes keys to the open commit dialog, which takes
+// all input except cancel and ctrl+c.
+func (m *Model) updateCommitDialog(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return tea.Quit
+	case key.Matches(msg, m.keys.Cancel):
+		m.showCommit = false
+		return nil
+	}
+	var cmd tea.Cmd
+	m.commitDialog, cmd = m.commitDialog.Update(msg)
+	return cmd
+}
+
+// showingConflict reports whether the right pane shows the conflict view
+// rather than the diff view.
+func (m *Model) showingConflict() bool {
+	return m.currentFile != nil && m.currentFile.Unmerged
+}
+
+// showingModule reports whether the right pane shows the module view.
+func (m *Model) showingModule() bool {
+	return m.currentFile != nil && isModuleFile(*m.currentFile)
+}
+
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.statusMsg = ""
+		if !key.Matches(msg, m.keys.ApplyFormat) {
+			m.confirmFormat = ""
+		}
+		if m.showCommit {
+			return m, m.updateCommitDialog(msg)
+		}
+		if m.blameInfo != nil {
+			m.blameInfo = nil
+			return m, nil
+		}
 		switch {
 		case key.Matches(msg, m.keys.Quit):
+			m.stopTests()
 			return m, tea.Quit
 		case key.Matches(msg, m.keys.Help):
 			m.showHelp = !m.showHelp
 			return m, nil
+		case key.Matches(msg, m.keys.OpenEditor):
+			return m, m.openInEditor()
+		case key.Matches(msg, m.keys.Commit):
+			m.showCommit = true
+			return m, tea.Batch(m.commitDialog.Open(), m.loadStagedFiles)
+		case key.Matches(msg, m.keys.History):
+			if m.showHistory {
+				return m, m.closeHistory()
+			}
+			return m, m.openHistory("")
+		case key.Matches(msg, m.keys.RunTests):
+			return m, m.runTests()
+		case key.Matches(msg, m.keys.TestResults):
+			m.toggleTests()
+			return m, nil
+		case key.Matches(msg, m.keys.Suppressed):
+			m.diffView.ToggleSuppressed()
+			return m, nil
+		case key.Matches(msg, m.keys.Blame):
+			return m, m.toggleBlame()
+		case key.Matches(msg, m.keys.BlameInfo):
+			return m, m.showBlameInfo()
+		case key.Matches(msg, m.keys.FormatDiff):
+			return m, m.toggleFormatDiff()
+		case key.Matches(msg, m.keys.ApplyFormat):
+			return m, m.applyFormat()
+		case key.Matches(msg, m.keys.Stashes):
+			if m.showHistory && m.commitList.Stashes() {
+				return m, m.closeHistory()
+			}
+			return m, m.openStashes()
+		case key.Matches(msg, m.keys.FileHistory):
+			file := m.fileList.SelectedFile()
+			if file == nil {
+				m.statusMsg = "No file selected"
+				return m, nil
+			}
+			return m, m.openHistory(file.Path)
+		case m.showHistory && key.Matches(msg, m.keys.Cancel):
+			return m, m.closeHistory()
 		case key.Matches(msg, m.keys.Tab):
-			if m.activePane == PaneFileList {
-				m.activePane = PaneDiffView
-			} else {
-				m.activePane = PaneFileList
+			panes := []Pane{PaneFileList, PaneDiffView}
+			if m.showHistory {
+				panes = append(panes, PaneCommitList)
+			}
+			if m.showTests {
+				panes = append(panes, PaneTestResults)
 			}
+			next := slices.Index(panes, m.activePane) + 1
+			m.activePane = panes[next%len(panes)]
 			return m, nil
+		case key.Matches(msg, m.keys.NextHunk), key.Matches(msg, m.keys.PrevHunk),
+			key.Matches(msg, m.keys.NextChange), key.Matches(msg, m.keys.PrevChange):
+			var cmd tea.Cmd
+			switch {
+			case m.showingConflict():
+				m.conflictView, cmd = m.conflictView.Update(msg)
+			case m.showingModule():
+				// The module view has no hunks to move between.
+			default:
+				m.diffView, cmd = m.diffView.Update(msg)
+			}
+			return m, cmd
+		}
+
+		if cmd, ok := m.handleDiffOptionKey(msg); ok {
+			return m, cmd
 		}
 
-		if m.activePane == PaneFileList {
+		if m.activePane == PaneTestResults {
+			m.testResults, _ = m.testResults.Update(msg)
+		} else if m.activePane == PaneCommitList {
+			if cmd, ok := m.handleStashKey(msg); ok {
+				return m, cmd
+			}
+			m.confirmDrop = ""
+			prev := m.commitList.SelectedCommit()
+			m.commitList, _ = m.commitList.Update(msg)
+			if next := m.commitList.SelectedCommit(); next != nil && (prev == nil || prev.Hash != next.Hash) {
+				cmds = append(cmds, m.selectCommit())
+			}
+		} else if m.activePane == PaneFileList {
 			prevFile := m.fileList.SelectedFile()
 			m.fileList, _ = m.fileList.Update(msg)
 			newFile := m.fileList.SelectedFile()
@@ -122,13 +446,26 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.currentFile = newFile
 				cmds = append(cmds, m.loadDiff(*newFile))
 			}
+		} else if m.showingConflict() {
+			var cmd tea.Cmd
+			m.conflictView, cmd = m.conflictView.Update(msg)
+			cmds = append(cmds, cmd)
+		} else if m.showingModule() {
+			m.moduleView, _ = m.moduleView.Update(msg)
 		} else {
 			m.diffView, _ = m.diffView.Update(msg)
 		}
 
 	case tea.MouseMsg:
 		if msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelDown {
-			m.diffView, _ = m.diffView.Update(msg)
+			switch {
+			case m.showingConflict():
+				m.conflictView, _ = m.conflictView.Update(msg)
+			case m.showingModule():
+				m.moduleView, _ = m.moduleView.Update(msg)
+			default:
+				m.diffView, _ = m.diffView.Update(msg)
+			}
 		}
 
 	case tea.WindowSizeMsg:
@@ -143,18 +480,94 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.files = msg.files
-		hadSelection := m.currentFile != nil
+		m.dropStaleCoverage(msg.files)
+		cmds = append(cmds, m.checkFormat(msg.files), m.maybeRunChecks(msg.files))
+		if m.showHistory {
+			break
+		}
 		m.fileList.SetFiles(msg.files)
 
-		if !hadSelection && len(msg.files) > 0 {
-			for i := 0; i < m.fileList.ItemCount(); i++ {
-				if file := m.fileList.SelectedFile(); file != nil {
-					m.currentFile = file
-					cmds = append(cmds, m.loadDiff(*file))
-					break
-				}
-				m.fileList.Update(tea.KeyMsg{Type: tea.KeyDown})
+		// Load the selection if it is new, or if the previously selected
+		// entry changed, e.g. a conflict that has been resolved.
+		if file := m.fileList.SelectedFile(); file == nil {
+			m.currentFile = nil
+			m.diffView.SetDiff(nil)
+		} else if m.currentFile == nil || *file != *m.currentFile {
+			m.currentFile = file
+			cmds = append(cmds, m.loadDiff(*file))
+		}
+
+	case logMsg:
+		if !m.showHistory {
+			break
+		}
+		if msg.err != nil {
+			m.statusMsg = msg.err.Error()
+			break
+		}
+		m.commitList.SetCommits(msg.commits, msg.path)
+		cmds = append(cmds, m.selectCommit())
+
+	case stashesMsg:
+		if !m.showHistory {
+			break
+		}
+		if msg.err != nil {
+			m.statusMsg = msg.err.Error()
+			break
+		}
+		m.commitList.SetStashes(msg.stashes)
+		cmds = append(cmds, m.selectCommit())
+
+	case stashOpMsg:
+		if msg.err != nil {
+			m.statusMsg = commitSummary(msg.output)
+			if m.statusMsg == "" {
+				m.statusMsg = msg.err.Error()
 			}
+		} else {
+			m.statusMsg = strings.ToUpper(msg.op[:1]) + msg.op[1:] + " " + msg.ref
+		}
+		cmds = append(cmds, m.loadFiles)
+		if m.showHistory && m.commitList.Stashes() {
+			cmds = append(cmds, m.loadStashes)
+		}
+
+	case commitFilesMsg:
+		if msg.err != nil {
+			m.statusMsg = msg.err.Error()
+			break
+		}
+		commit := m.commitList.SelectedCommit()
+		if !m.showHistory || commit == nil || commit.Hash != msg.hash {
+			break
+		}
+		label := ""
+		if m.commitList.Stashes() {
+			label = "STASH " + commit.Short
+		}
+		m.fileList.SetCommitLabel(label)
+		m.fileList.SetFiles(msg.files)
+		if file := m.fileList.SelectedFile(); file == nil {
+			m.currentFile = nil
+			m.diffView.SetDiff(nil)
+		} else {
+			m.currentFile = file
+			cmds = append(cmds, m.loadDiff(*file))
+		}
+
+	case fileJumpMsg:
+		prevFile := m.fileList.SelectedFile()
+		if msg.forward {
+			m.fileList.SelectNext()
+		} else {
+			m.fileList.SelectPrev()
+		}
+		newFile := m.fileList.SelectedFile()
+		if newFile != nil && (prevFile == nil || *prevFile != *newFile) {
+			m.currentFile = newFile
+			m.pendingJump = msg.target
+			cmds = append(cmds, m.loadDiff(*newFile))
 		}
 
 	case diffMsg:
@@ -163,10 +576,190 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.diffView.SetDiff(msg.diff)
+		if m.pendingJump != jumpNone && m.currentFile != nil && msg.diff != nil &&
+			msg.diff.Path == m.currentFile.Path {
+			m.diffView.Jump(m.pendingJump)
+			m.pendingJump = jumpNone
+		}
+		m.updateDiffFindings()
+		m.updateDiffCoverage()
+		m.updateDiffComplexity()
+		cmds = append(cmds, m.ensureBlame())
+
+	case testsStartedMsg:
+		if msg.err != nil {
+			m.statusMsg = "tests: " + msg.err.Error()
+			m.stopTests()
+			break
+		}
+		if len(msg.affected.Packages) == 0 {
+			m.statusMsg = "No packages to test"
+			m.stopTests()
+			break
+		}
+		m.testFiles = msg.affected.Files
+		m.testResults.Start(msg.affected.Packages)
+		m.setTestMarks()
+		if !m.showTests {
+			m.toggleTests()
+		}
+		cmds = append(cmds, waitForTestEvent(msg.events))
+
+	case testEventMsg:
+		m.testResults.AddEvent(msg.event)
+		m.setTestMarks()
+		cmds = append(cmds, waitForTestEvent(msg.events))
+
+	case testsDoneMsg:
+		m.testResults.Finish(msg.err)
+		m.stopTests()
+		m.setTestMarks()
+		m.coverage = msg.coverage
+		m.coveragePercent = msg.percent
+		m.setCoverageMarks()
+		m.updateDiffCoverage()
+
+	case checksMsg:
+		m.checking = false
+		if msg.err != nil {
+			m.statusMsg = "checks: " + msg.err.Error()
+			break
+		}
+		m.findings = msg.findings
+		m.complexity = msg.complexity
+		m.setFindingMarks()
+		m.updateDiffFindings()
+		m.updateDiffComplexity()
+
+	case formatMsg:
+		changed := m.currentFile != nil &&
+			m.unformatted[m.currentFile.Path] != msg.unformatted[m.currentFile.Path]
+		m.unformatted = msg.unformatted
+		m.setFormatMarks()
+		if changed && m.showFormat {
+			cmds = append(cmds, m.loadDiff(*m.currentFile))
+		}
+
+	case formatAppliedMsg:
+		switch {
+		case msg.err != nil:
+			m.statusMsg = "gofmt: " + msg.err.Error()
+		case msg.changed:
+			m.statusMsg = "Formatted " + msg.path
+		default:
+			m.statusMsg = msg.path + " is formatted"
+		}
+		cmds = append(cmds, m.loadFiles)
+		if m.currentFile != nil {
+			cmds = append(cmds, m.loadDiff(*m.currentFile))
+		}
+
+	case blameMsg:
+		if !m.showBlame || msg.key != m.blameFor {
+			break
+		}
+		if msg.err != nil {
+			m.statusMsg = "blame: " + msg.err.Error()
+			break
+		}
+		m.diffView.SetBlame(msg.blame)
+
+	case blameInfoMsg:
+		if msg.err != nil {
+			m.statusMsg = msg.err.Error()
+			break
+		}
+		m.blameInfo = &msg
+
+	case conflictMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.pendingJump = jumpNone
+		m.conflictView.SetConflict(msg.conflict)
+
+	case moduleMsg:
+		if m.currentFile == nil || m.currentFile.Path != msg.path {
+			break
+		}
+		m.pendingJump = jumpNone
+		m.moduleView.SetModule(msg.path, msg.diff, msg.err)
+
+	case resolveMsg:
+		cmds = append(cmds, m.resolveConflict(msg))
+
+	case resolveDeletionMsg:
+		cmds = append(cmds, m.resolveDeletion(msg))
+
+	case markResolvedMsg:
+		cmds = append(cmds, m.markResolved(msg.path))
+
+	case resolvedMsg:
+		switch {
+		case msg.err != nil:
+			m.statusMsg = msg.err.Error()
+		case msg.staged:
+			m.statusMsg = msg.path + " resolved and staged"
+		}
+		cmds = append(cmds, m.loadFiles)
+		if m.currentFile != nil {
+			cmds = append(cmds, m.loadDiff(*m.currentFile))
+		}
+
+	case stagedFilesMsg:
+		if msg.err != nil {
+			m.statusMsg = msg.err.Error()
+		}
+		m.commitDialog.SetStaged(msg.files)
+
+	case headMessageRequestMsg:
+		cmds = append(cmds, m.loadHeadMessage)
+
+	case headMessageMsg:
+		if strings.TrimSpace(m.commitDialog.Message()) == "" {
+			m.commitDialog.SetMessage(msg.message)
+		}
+
+	case editorDoneMsg:
+		if msg.err != nil {
+			m.statusMsg = "editor: " + msg.err.Error()
+		} else {
+			m.commitDialog.SetMessage(msg.message)
+		}
+
+	case editorLineMsg:
+		if msg.err != nil {
+			m.statusMsg = msg.err.Error()
+		} else {
+			cmds = append(cmds, m.editAt(msg.path, msg.line))
+		}
+
+	case editorClosedMsg:
+		if msg.err != nil {
+			m.statusMsg = "editor: " + msg.err.Error()
+		}
+		cmds = append(cmds, m.loadFiles)
+		if m.currentFile != nil {
+			cmds = append(cmds, m.loadDiff(*m.currentFile))
+		}
+
+	case commitRequestMsg:
+		cmds = append(cmds, m.commit(msg))
+
+	case commitDoneMsg:
+		if m.commitDialog.Finish(msg.output, msg.err) {
+			m.showCommit = false
+			m.statusMsg = commitSummary(msg.output)
+			// HEAD moved, so the blame is out of date.
+			m.blameFor = fileKey{}
+		}
+		cmds = append(cmds, m.loadFiles)
 
 	case tickMsg:
 		cmds = append(cmds, m.loadFiles, m.doTick())
-		if m.currentFile != nil {
+		// Commits do not change, so only working tree diffs are reloaded.
+		if m.currentFile != nil && !m.showHistory {
 			cmds = append(cmds, m.loadDiff(*m.currentFile))
 		}
 	}
@@ -187,8 +780,25 @@ func (m *Model) updateLayout() {
 	}
 	diffViewWidth := m.width - fileListWidth - 1
 
-	m.fileList.SetSize(fileListWidth, availableHeight)
-	m.diffView.SetSize(diffViewWidth, availableHeight)
+	if m.showHistory {
+		// The commit list sits above the file list; each has a border.
+		commitListHeight := (availableHeight - 2) * 3 / 5
+		m.commitList.SetSize(fileListWidth, commitListHeight)
+		m.fileList.SetSize(fileListWidth, availableHeight-2-commitListHeight)
+	} else {
+		m.fileList.SetSize(fileListWidth, availableHeight)
+	}
+	diffViewHeight := availableHeight
+	if m.showTests {
+		// The test results sit below the diff; each has a border.
+		testResultsHeight := (availableHeight - 2) * 2 / 5
+		diffViewHeight = availableHeight - 2 - testResultsHeight
+		m.testResults.SetSize(diffViewWidth, testResultsHeight)
+	}
+	m.diffView.SetSize(diffViewWidth, diffViewHeight)
+	m.conflictView.SetSize(diffViewWidth, diffViewHeight)
+	m.moduleView.SetSize(diffViewWidth, diffViewHeight)
+	m.commitDialog.SetSize(m.width, m.height)
 }
 
 func (m *Model) View() string {
@@ -204,10 +814,33 @@ func (m *Model) View() string {
 		return m.renderHelp()
 	}
 
+	if m.showCommit {
+		return m.commitDialog.View() + "\n" + m.renderStatusBar()
+	}
+
+	if m.blameInfo != nil {
+		return m.renderBlameInfo() + "\n" + m.renderStatusBar()
+	}
+
 	var b strings.Builder
 
 	fileListView := m.fileList.View(m.activePane == PaneFileList)
+	if m.showHistory {
+		fileListView = lipgloss.JoinVertical(lipgloss.Left,
+			m.commitList.View(m.activePane == PaneCommitList),
+			fileListView)
+	}
 	diffViewView := m.diffView.View(m.activePane == PaneDiffView)
+	if m.showingConflict() {
+		diffViewView = m.conflictView.View(m.activePane == PaneDiffView)
+	} else if m.showingModule() {
+		diffViewView = m.moduleView.View(m.activePane == PaneDiffView)
+	}
+	if m.showTests {
+		diffViewView = lipgloss.JoinVertical(lipgloss.Left,
+			diffViewView,
+			m.testResults.View(m.activePane == PaneTestResults))
+	}
 
 	content := lipgloss.JoinHorizontal(
 		lipgloss.Top,
@@ -231,6 +864,21 @@ func (m *Model) renderStatusBar() string {
 		Render("  │  ")
 
 	var items []string
+	if m.statusMsg != "" {
+		items = append(items, m.styles.StatusMessage.Render(truncate(m.statusMsg, m.width/3)))
+	}
+	switch {
+	case m.showTests:
+	case m.testResults.Running():
+		items = append(items, m.styles.HelpDesc.Render("testing…"))
+	case m.testResults.FailedCount() > 0:
+		items = append(items, m.styles.FindingHigh.Render("tests failed"))
+	}
+	if m.checking {
+		items = append(items, m.styles.HelpDesc.Render("checking…"))
+	} else if item := m.findingsSummary(); item != "" {
+		items = append(items, item)
+	}
 	items = append(items, m.styles.HelpKey.Render("j/k")+" "+m.styles.HelpDesc.Render("up/down"))
 	items = append(items, m.styles.HelpKey.Render("Tab")+" "+m.styles.HelpDesc.Render("switch pane"))
 	items = append(items, m.styles.HelpKey.Render("g/G")+" "+m.styles.HelpDesc.Render("top/bottom"))
@@ -239,6 +887,10 @@ func (m *Model) renderStatusBar() string {
 
 	left := strings.Join(items, sep)
 	right := m.renderLogo()
+	// The logo gives way to status items when they need the room.
+	if lipgloss.Width(left)+lipgloss.Width(right)+2 > m.width {
+		right = ""
+	}
 
 	contentWidth := lipgloss.Width(left) + lipgloss.Width(right)
 	gap := m.width - contentWidth - 2
@@ -301,6 +953,30 @@ func (m *Model) renderHelp() string {
 		{"g / G", "Jump to top/bottom"},
 		{"Ctrl+u / Ctrl+d", "Page up/down"},
 		{"Tab", "Switch between file list and diff view"},
+		{"] / [", "Next/previous hunk"},
+		{"n / N", "Next/previous change"},
+		{"{ / }", "Expand context above/below hunk"},
+		{"F", "Toggle full file"},
+		{"+ / -", "More/less context lines"},
+		{"w", "Toggle ignoring whitespace"},
+		{"B", "Toggle ignoring blank lines"},
+		{"m", "Toggle moved-code detection"},
+		{"L", "Load the rest of a truncated diff"},
+		{"o / t / a", "Resolve conflict with ours/theirs/both"},
+		{"S", "Mark a conflicted file resolved"},
+		{"e", "Open file in editor at this line"},
+		{"f", "Toggle showing gofmt changes for unformatted files"},
+		{"W", "Show gofmt changes for the current file, and press again to apply them"},
+		{"b", "Toggle blame for removed and context lines"},
+		{"i", "Show the commit behind the selected line"},
+		{"T", "Run the tests of affected packages"},
+		{"R", "Toggle the test results pane"},
+		{"s", "Show or hide suppressed findings"},
+		{"H", "Browse commit history"},
+		{"h", "Browse history of the selected file"},
+		{"z", "Browse stashes"},
+		{"a / p / d", "Apply, pop or drop the selected stash"},
+		{"c", "Commit staged changes"},
 		{"?", "Toggle this help"},
 		{"q / Ctrl+c", "Quit"},
 	}
@@ -329,3 +1005,15 @@ func (m *Model) renderHelp() string {
 
 	return b.String()
 }
+
+// commitSummary picks git's "[branch hash] subject" line out of the output
+// of git commit, which may also contain hook output.
+func commitSummary(output string) string {
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "[") {
+			return line
+		}
+	}
+	return lines[0]
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"grua/internal/git"
+	"grua/internal/gomod"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ModuleView shows how a change to go.mod alters the module's
+// dependencies, in place of its diff.
+type ModuleView struct {
+	path     string
+	diff     *gomod.Diff
+	err      error
+	viewport viewport.Model
+	styles   *Styles
+	keys     KeyMap
+	width    int
+	height   int
+	ready    bool
+}
+
+// moduleMsg carries the comparison of a go.mod file's two versions.
+type moduleMsg struct {
+	path string
+	diff *gomod.Diff
+	err  error
+}
+
+// NewModuleView returns an empty module view.
+func NewModuleView(styles *Styles, keys KeyMap) *ModuleView {
+	return &ModuleView{styles: styles, keys: keys}
+}
+
+// isModuleFile reports whether file is a changed go.mod in the working
+// tree, which the module view shows.
+func isModuleFile(file git.FileStatus) bool {
+	return path.Base(file.Path) == "go.mod" && file.Commit == "" && !file.Unmerged
+}
+
+// SetSize sets the outer size of the view, including its border.
+func (v *ModuleView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+
+	viewportHeight := height - 3
+	viewportWidth := width - 4
+	if !v.ready {
+		v.viewport = viewport.New(viewportWidth, viewportHeight)
+		v.ready = true
+	} else {
+		v.viewport.Width = viewportWidth
+		v.viewport.Height = viewportHeight
+	}
+	v.render()
+}
+
+// SetModule shows the comparison for the go.mod at path, or the error
+// that prevented it.
+func (v *ModuleView) SetModule(path string, diff *gomod.Diff, err error) {
+	isNewFile := v.path != path
+	v.path, v.diff, v.err = path, diff, err
+	prevYOffset := v.viewport.YOffset
+	v.render()
+	if isNewFile {
+		v.viewport.GotoTop()
+	} else {
+		v.viewport.SetYOffset(prevYOffset)
+	}
+}
+
+// Clear forgets the shown module while another one loads.
+func (v *ModuleView) Clear() {
+	v.diff, v.err = nil, nil
+	v.render()
+}
+
+func (v *ModuleView) render() {
+	if v.diff == nil || !v.ready {
+		v.viewport.SetContent("")
+		return
+	}
+	d := v.diff
+	contentWidth := v.width - 6
+
+	var rows []string
+	section := func(title string) {
+		if len(rows) > 0 {
+			rows = append(rows, "")
+		}
+		rows = append(rows, v.styles.HunkHeader.Render(title))
+	}
+
+	if d.OldGo != d.NewGo || d.OldToolchain != d.NewToolchain {
+		section("VERSIONS")
+		if d.OldGo != d.NewGo {
+			rows = append(rows, v.versionRow("go", d.OldGo, d.NewGo))
+		}
+		if d.OldToolchain != d.NewToolchain {
+			rows = append(rows, v.versionRow("toolchain", d.OldToolchain, d.NewToolchain))
+		}
+	}
+
+	if len(d.Modules) > 0 {
+		section(fmt.Sprintf("REQUIREMENTS (%d)", len(d.Modules)))
+		pathWidth := 0
+		for _, mc := range d.Modules {
+			pathWidth = max(pathWidth, len(mc.Path))
+		}
+		pathWidth = min(pathWidth, contentWidth/2)
+		for _, mc := range d.Modules {
+			rows = append(rows, v.moduleRow(mc, pathWidth, contentWidth))
+		}
+	}
+
+	if len(d.AddedReplaces) > 0 || len(d.RemovedReplaces) > 0 {
+		section("REPLACE")
+		for _, r := range d.AddedReplaces {
+			rows = append(rows, v.styles.ModuleAdded.Render(truncate("+ "+r.String(), contentWidth)))
+		}
+		for _, r := range d.RemovedReplaces {
+			rows = append(rows, v.styles.ModuleRemoved.Render(truncate("- "+r.String(), contentWidth)))
+		}
+	}
+
+	if len(d.UnmatchedSums) > 0 {
+		section("GO.SUM")
+		for _, mv := range d.UnmatchedSums {
+			text := fmt.Sprintf("! %s %s is not required by go.mod", mv.Path, mv.Version)
+			rows = append(rows, v.styles.ModuleDowngraded.Render(truncate(text, contentWidth)))
+		}
+	}
+
+	v.viewport.SetContent(strings.Join(rows, "\n"))
+}
+
+func (v *ModuleView) versionRow(name, old, new string) string {
+	var text string
+	switch {
+	case old == "":
+		text = "added " + new
+	case new == "":
+		text = "removed " + old
+	default:
+		text = old + " → " + new
+	}
+	return fmt.Sprintf("  %-10s %s", name, text)
+}
+
+func (v *ModuleView) moduleRow(mc gomod.ModuleChange, pathWidth, width int) string {
+	var sign, versions string
+	var style lipgloss.Style
+	switch mc.Kind {
+	case gomod.Added:
+		sign, versions, style = "+", mc.New, v.styles.ModuleAdded
+	case gomod.Removed:
+		sign, versions, style = "-", mc.Old, v.styles.ModuleRemoved
+	case gomod.Upgraded:
+		sign, versions, style = "↑", mc.Old+" → "+mc.New, v.styles.ModuleUpgraded
+	case gomod.Downgraded:
+		sign, versions, style = "↓", mc.Old+" → "+mc.New, v.styles.ModuleDowngraded
+	}
+	text := fmt.Sprintf("%s %-*s  %s", sign, pathWidth, truncate(mc.Path, pathWidth), versions)
+	row := style.Render(truncate(text, width))
+	if mc.Indirect {
+		row += v.styles.CommitMeta.Render(" // indirect")
+	}
+	return row
+}
+
+// Update scrolls the view.
+func (v *ModuleView) Update(msg tea.Msg) (*ModuleView, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, v.keys.Up):
+			v.viewport.ScrollUp(1)
+		case key.Matches(msg, v.keys.Down):
+			v.viewport.ScrollDown(1)
+		case key.Matches(msg, v.keys.Top):
+			v.viewport.GotoTop()
+		case key.Matches(msg, v.keys.Bottom):
+			v.viewport.GotoBottom()
+		case key.Matches(msg, v.keys.PageUp):
+			v.viewport.HalfViewUp()
+		case key.Matches(msg, v.keys.PageDown):
+			v.viewport.HalfViewDown()
+		}
+	default:
+		v.viewport, cmd = v.viewport.Update(msg)
+	}
+
+	return v, cmd
+}
+
+// View renders the view, highlighting its border when active.
+func (v *ModuleView) View(active bool) string {
+	title := v.styles.DiffTitle.Render(v.path) + " " + v.styles.DiffFileLabel.Render("dependencies")
+
+	dim := lipgloss.NewStyle().Foreground(ColorDim).Italic(true)
+	var content string
+	switch {
+	case v.err != nil:
+		content = dim.Render("Cannot compare go.mod: " + v.err.Error())
+	case v.diff == nil:
+		content = dim.Render("Loading module...")
+	case v.diff.Empty():
+		content = dim.Render("No dependency changes")
+	default:
+		content = v.viewport.View()
+	}
+
+	borderStyle := v.styles.DiffBorder
+	if active {
+		borderStyle = v.styles.DiffBorderActive
+	}
+
+	return borderStyle.
+		Width(v.width).
+		Height(v.height).
+		Render(title + "\n" + content)
+}
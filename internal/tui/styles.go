@@ -3,23 +3,27 @@ package tui
 import "github.com/charmbracelet/lipgloss"
 
 var (
-	ColorBorder      = lipgloss.Color("#44475A")
-	ColorTitle       = lipgloss.Color("#FFD700")
-	ColorStaged      = lipgloss.Color("#FF79C6")
-	ColorUnstaged    = lipgloss.Color("#8BE9FD")
-	ColorSelected    = lipgloss.Color("#BD93F9")
-	ColorHunk        = lipgloss.Color("#00D7FF")
-	ColorLineNum     = lipgloss.Color("#6272A4")
-	ColorAddedBg     = lipgloss.Color("#1B4B1B")
-	ColorRemovedBg   = lipgloss.Color("#4B1818")
-	ColorAddedFg     = lipgloss.Color("#69FF94")
-	ColorRemovedFg   = lipgloss.Color("#FF6B6B")
-	ColorDim         = lipgloss.Color("#6272A4")
-	ColorFg          = lipgloss.Color("#F8F8F2")
-	ColorBg          = lipgloss.Color("#282A36")
-	ColorHighlight   = lipgloss.Color("#44475A")
-	ColorStatusBadge = lipgloss.Color("#50FA7B")
-	ColorStatusBarBg = lipgloss.Color("#1E1F29")
+	ColorBorder         = lipgloss.Color("#44475A")
+	ColorTitle          = lipgloss.Color("#FFD700")
+	ColorStaged         = lipgloss.Color("#FF79C6")
+	ColorUnstaged       = lipgloss.Color("#8BE9FD")
+	ColorSelected       = lipgloss.Color("#BD93F9")
+	ColorHunk           = lipgloss.Color("#00D7FF")
+	ColorLineNum        = lipgloss.Color("#6272A4")
+	ColorAddedBg        = lipgloss.Color("#1B4B1B")
+	ColorRemovedBg      = lipgloss.Color("#4B1818")
+	ColorAddedFg        = lipgloss.Color("#69FF94")
+	ColorRemovedFg      = lipgloss.Color("#FF6B6B")
+	ColorMovedFg        = lipgloss.Color("#8BE9FD")
+	ColorMovedAddedBg   = lipgloss.Color("#1B3B4B")
+	ColorMovedRemovedBg = lipgloss.Color("#3B1B4B")
+	ColorDim            = lipgloss.Color("#6272A4")
+	ColorFg             = lipgloss.Color("#F8F8F2")
+	ColorBg             = lipgloss.Color("#282A36")
+	ColorHighlight      = lipgloss.Color("#44475A")
+	ColorStatusBadge    = lipgloss.Color("#50FA7B")
+	ColorStatusBarBg    = lipgloss.Color("#1E1F29")
+	ColorConflict       = lipgloss.Color("#FFB86C")
 
 	LogoGradient = []lipgloss.Color{
 		lipgloss.Color("#E9B8FF"),
@@ -42,12 +46,33 @@ type Styles struct {
 	StagedHeader         lipgloss.Style
 	UnstagedHeader       lipgloss.Style
 	UnversionedHeader    lipgloss.Style
+	ConflictHeader       lipgloss.Style
+	ConflictLabel        lipgloss.Style
+	ConflictSelected     lipgloss.Style
+	StatusMessage        lipgloss.Style
+	CommitHeader         lipgloss.Style
+	CommitHash           lipgloss.Style
+	CommitMeta           lipgloss.Style
+	BlameAuthor          lipgloss.Style
+	FileMark             lipgloss.Style
+	FindingHigh          lipgloss.Style
+	FindingMedium        lipgloss.Style
+	FindingLow           lipgloss.Style
+	Covered              lipgloss.Style
+	Uncovered            lipgloss.Style
+	ModuleAdded          lipgloss.Style
+	ModuleRemoved        lipgloss.Style
+	ModuleUpgraded       lipgloss.Style
+	ModuleDowngraded     lipgloss.Style
 	FileItem             lipgloss.Style
 	FileItemSelected     lipgloss.Style
 	StatusBadge          lipgloss.Style
 	DiffBorder           lipgloss.Style
 	DiffBorderActive     lipgloss.Style
 	DiffTitle            lipgloss.Style
+	DiffFlag             lipgloss.Style
+	DiffFileLabel        lipgloss.Style
+	FileItemDeleted      lipgloss.Style
 	HunkHeader           lipgloss.Style
 	LineNumber           lipgloss.Style
 	AddedLine            lipgloss.Style
@@ -101,10 +126,73 @@ func NewStyles() *Styles {
 		MarginTop(1).
 		MarginBottom(0)
 
+	s.ConflictHeader = lipgloss.NewStyle().
+		Foreground(ColorConflict).
+		Bold(true).
+		MarginBottom(0)
+
+	s.CommitHeader = lipgloss.NewStyle().
+		Foreground(ColorTitle).
+		Bold(true)
+
+	s.CommitHash = lipgloss.NewStyle().
+		Foreground(ColorHunk)
+
+	s.CommitMeta = lipgloss.NewStyle().
+		Foreground(ColorDim)
+
+	s.BlameAuthor = lipgloss.NewStyle().
+		Foreground(ColorUnstaged)
+
+	s.FileMark = lipgloss.NewStyle().
+		Foreground(ColorConflict)
+
+	s.FindingHigh = lipgloss.NewStyle().
+		Foreground(ColorRemovedFg).
+		Bold(true)
+
+	s.FindingMedium = lipgloss.NewStyle().
+		Foreground(ColorConflict)
+
+	s.FindingLow = lipgloss.NewStyle().
+		Foreground(ColorUnstaged)
+
+	s.Covered = lipgloss.NewStyle().
+		Foreground(ColorAddedFg)
+
+	s.Uncovered = lipgloss.NewStyle().
+		Foreground(ColorRemovedFg)
+
+	s.ModuleAdded = lipgloss.NewStyle().
+		Foreground(ColorAddedFg)
+
+	s.ModuleRemoved = lipgloss.NewStyle().
+		Foreground(ColorRemovedFg)
+
+	s.ModuleUpgraded = lipgloss.NewStyle().
+		Foreground(ColorUnstaged)
+
+	s.ModuleDowngraded = lipgloss.NewStyle().
+		Foreground(ColorConflict)
+
+	s.ConflictLabel = lipgloss.NewStyle().
+		Foreground(ColorConflict).
+		Bold(true)
+
+	s.ConflictSelected = lipgloss.NewStyle().
+		Foreground(ColorConflict).
+		Bold(true).
+		Underline(true)
+
 	s.FileItem = lipgloss.NewStyle().
 		Foreground(ColorFg).
 		PaddingLeft(2)
 
+	s.FileItemDeleted = lipgloss.NewStyle().
+		Foreground(ColorRemovedFg).
+		Strikethrough(true).
+		PaddingLeft(2)
+
 	s.FileItemSelected = lipgloss.NewStyle().
 		Foreground(ColorBg).
 		Background(ColorSelected).
@@ -129,6 +217,15 @@ func NewStyles() *Styles {
 		Foreground(ColorTitle).
 		Bold(true)
 
+	s.DiffFlag = lipgloss.NewStyle().
+		Foreground(ColorDim).
+		Italic(true)
+
+	s.DiffFileLabel = lipgloss.NewStyle().
+		Foreground(ColorBg).
+		Background(ColorUnstaged).
+		Padding(0, 1)
+
 	s.HunkHeader = lipgloss.NewStyle().
 		Foreground(ColorHunk).
 		Bold(true)
@@ -160,6 +257,11 @@ func NewStyles() *Styles {
 		Foreground(ColorSelected).
 		Bold(true)
 
+	s.StatusMessage = lipgloss.NewStyle().
+		Background(ColorStatusBarBg).
+		Foreground(ColorConflict).
+		Bold(true)
+
 	s.HelpDesc = lipgloss.NewStyle().
 		Background(ColorStatusBarBg).
 		Foreground(lipgloss.Color("#8B8B9E"))
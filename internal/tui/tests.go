@@ -0,0 +1,378 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"grua/internal/checks"
+	"grua/internal/git"
+	"grua/internal/gotest"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestResults shows the progress and failures of a test run: a summary
+// line per package, and the output of each failing test.
+type TestResults struct {
+	lines    []string
+	packages []string
+	done     int
+	failed   map[string]bool
+	running  bool
+	// output buffers the output of each running test, and of each package
+	// outside its tests, until the result is known.
+	output map[string][]string
+	offset int
+	// follow keeps the newest lines in view while tests run, until the
+	// list is scrolled up.
+	follow bool
+	width  int
+	height int
+	styles *Styles
+	keys   KeyMap
+}
+
+func NewTestResults(styles *Styles, keys KeyMap) *TestResults {
+	return &TestResults{
+		styles: styles,
+		keys:   keys,
+	}
+}
+
+func (t *TestResults) SetSize(width, height int) {
+	t.width = width
+	t.height = height
+	t.clampOffset()
+}
+
+// Start clears the results for a run of pkgs.
+func (t *TestResults) Start(pkgs []string) {
+	t.lines = nil
+	t.packages = pkgs
+	t.done = 0
+	t.failed = make(map[string]bool)
+	t.output = make(map[string][]string)
+	t.running = true
+	t.offset = 0
+	t.follow = true
+}
+
+// Finish marks the run as over, with err if go test could not run.
+func (t *TestResults) Finish(err error) {
+	t.running = false
+	if err != nil {
+		t.add(t.styles.FindingHigh.Render("go test: " + err.Error()))
+	}
+}
+
+// Failed reports whether tests in pkg failed.
+func (t *TestResults) Failed(pkg string) bool {
+	return t.failed[pkg]
+}
+
+// FailedCount returns how many packages failed.
+func (t *TestResults) FailedCount() int {
+	return len(t.failed)
+}
+
+// Running reports whether a run is in progress.
+func (t *TestResults) Running() bool {
+	return t.running
+}
+
+// AddEvent records an event from go test.
+func (t *TestResults) AddEvent(e gotest.Event) {
+	id := e.Package + " " + e.Test
+	switch e.Action {
+	case "output":
+		if e.Package == "" {
+			t.add(strings.TrimRight(e.Output, "\n"))
+			return
+		}
+		t.output[id] = append(t.output[id], strings.TrimRight(e.Output, "\n"))
+	case "build-output":
+		t.add(strings.TrimRight(e.Output, "\n"))
+	case "pass", "skip":
+		if e.Test == "" {
+			t.finishPackage(e)
+		}
+		delete(t.output, id)
+	case "fail":
+		if e.Test == "" {
+			t.finishPackage(e)
+			break
+		}
+		t.add(t.styles.FindingHigh.Render(fmt.Sprintf("--- FAIL: %s (%.2fs)", e.Test, e.Elapsed)))
+		for _, line := range t.output[id] {
+			if !isTestMarker(line) {
+				t.add(line)
+			}
+		}
+		delete(t.output, id)
+	}
+}
+
+// finishPackage adds the summary line for a package. A package that failed
+// outside any test, such as in TestMain or an init function, shows its
+// output too.
+func (t *TestResults) finishPackage(e gotest.Event) {
+	id := e.Package + " "
+	t.done++
+	switch e.Action {
+	case "pass":
+		t.add(t.styles.StagedHeader.Render("ok   ") + fmt.Sprintf("%s %.2fs", e.Package, e.Elapsed))
+	case "skip":
+		t.add(t.styles.CommitMeta.Render("?    " + e.Package + " [no test files]"))
+	case "fail":
+		t.failed[e.Package] = true
+		for _, line := range t.output[id] {
+			if !isTestMarker(line) && !strings.HasPrefix(line, "FAIL") {
+				t.add(line)
+			}
+		}
+		t.add(t.styles.FindingHigh.Render("FAIL ") + fmt.Sprintf("%s %.2fs", e.Package, e.Elapsed))
+	}
+	delete(t.output, id)
+}
+
+// isTestMarker reports whether line is one of the lines go test prints
+// around each test, which the results already summarise.
+func isTestMarker(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, prefix := range []string{"=== ", "--- PASS", "--- FAIL", "--- SKIP"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return trimmed == "PASS"
+}
+
+func (t *TestResults) add(line string) {
+	t.lines = append(t.lines, line)
+	if t.follow {
+		t.offset = len(t.lines)
+	}
+	t.clampOffset()
+}
+
+// visibleLines is how many lines fit below the title.
+func (t *TestResults) visibleLines() int {
+	return max(t.height-1, 1)
+}
+
+func (t *TestResults) clampOffset() {
+	t.offset = max(min(t.offset, len(t.lines)-t.visibleLines()), 0)
+}
+
+func (t *TestResults) scroll(delta int) {
+	t.offset += delta
+	t.clampOffset()
+	t.follow = t.offset >= len(t.lines)-t.visibleLines()
+}
+
+func (t *TestResults) Update(msg tea.Msg) (*TestResults, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, t.keys.Up):
+			t.scroll(-1)
+		case key.Matches(msg, t.keys.Down):
+			t.scroll(1)
+		case key.Matches(msg, t.keys.Top):
+			t.scroll(-len(t.lines))
+		case key.Matches(msg, t.keys.Bottom):
+			t.scroll(len(t.lines))
+		case key.Matches(msg, t.keys.PageUp):
+			t.scroll(-t.visibleLines() / 2)
+		case key.Matches(msg, t.keys.PageDown):
+			t.scroll(t.visibleLines() / 2)
+		}
+	}
+	return t, nil
+}
+
+func (t *TestResults) View(active bool) string {
+	borderStyle := t.styles.FileListBorder
+	if active {
+		borderStyle = t.styles.FileListBorderActive
+	}
+	innerWidth := max(t.width-4, 1)
+
+	title := fmt.Sprintf(" ▾ TESTS %d/%d packages", t.done, len(t.packages))
+	if t.running {
+		title += " · running…"
+	} else if len(t.failed) > 0 {
+		title += fmt.Sprintf(" · %d failed", len(t.failed))
+	}
+	lines := []string{t.styles.CommitHeader.Render(truncate(title, innerWidth))}
+
+	end := min(t.offset+t.visibleLines(), len(t.lines))
+	for _, line := range t.lines[t.offset:end] {
+		lines = append(lines, " "+truncate(strings.ReplaceAll(line, "\t", "    "), innerWidth-1))
+	}
+
+	return borderStyle.
+		Width(t.width).
+		Height(t.height).
+		Render(strings.Join(lines, "\n"))
+}
+
+// testsStartedMsg reports the packages a test run covers, and the channel
+// its events arrive on.
+type testsStartedMsg struct {
+	affected *gotest.Affected
+	events   <-chan tea.Msg
+	err      error
+}
+
+type testEventMsg struct {
+	event  gotest.Event
+	events <-chan tea.Msg
+}
+
+// testsDoneMsg ends a test run with the coverage of the changed files.
+type testsDoneMsg struct {
+	err      error
+	coverage map[string]gotest.Coverage
+	percent  map[string]int
+}
+
+// runTests starts go test for the packages affected by the changed files.
+// Events are passed through a channel so results show as they arrive.
+func (m *Model) runTests() tea.Cmd {
+	if m.cancelTests != nil {
+		m.statusMsg = "Tests are already running"
+		return nil
+	}
+	var paths []string
+	for _, file := range checks.Targets(m.files) {
+		paths = append(paths, file.Path)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelTests = cancel
+	m.testedSig = m.checkSignature(m.files)
+	files := m.files
+	repoPath := m.repoPath
+
+	return func() tea.Msg {
+		affected, err := gotest.FindAffected(repoPath, paths)
+		if err != nil {
+			return testsStartedMsg{err: err}
+		}
+		events := make(chan tea.Msg, 64)
+		go func() {
+			defer close(events)
+			done := testsDoneMsg{coverage: make(map[string]gotest.Coverage), percent: make(map[string]int)}
+			for _, mod := range affected.Modules {
+				coverage, percent, err := m.runModuleTests(ctx, mod, affected, files, events)
+				if err != nil {
+					done = testsDoneMsg{err: err}
+					break
+				}
+				maps.Copy(done.coverage, coverage)
+				maps.Copy(done.percent, percent)
+			}
+			events <- done
+		}()
+		return testsStartedMsg{affected: affected, events: events}
+	}
+}
+
+// runModuleTests runs the tests of one module's affected packages from the
+// module's root and returns the coverage of the changed files in it.
+func (m *Model) runModuleTests(ctx context.Context, mod gotest.Module, affected *gotest.Affected, files []git.FileStatus, events chan tea.Msg) (map[string]gotest.Coverage, map[string]int, error) {
+	profile, err := os.CreateTemp("", "grua-cover-*.out")
+	if err != nil {
+		return nil, nil, err
+	}
+	profile.Close()
+	defer os.Remove(profile.Name())
+
+	cov := &gotest.Cover{Profile: profile.Name(), Packages: coveredPackages(affected, mod)}
+	err = gotest.Run(ctx, mod.Dir, mod.Packages, cov, func(e gotest.Event) {
+		events <- testEventMsg{event: e, events: events}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	coverage, percent := m.readCoverage(profile.Name(), affected.Files, files)
+	return coverage, percent, nil
+}
+
+// coveredPackages returns the packages of mod containing changed files,
+// whose coverage is measured.
+func coveredPackages(affected *gotest.Affected, mod gotest.Module) []string {
+	var pkgs []string
+	for _, pkg := range affected.Files {
+		if slices.Contains(mod.Packages, pkg) && !slices.Contains(pkgs, pkg) {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	slices.Sort(pkgs)
+	return pkgs
+}
+
+// readCoverage reads the coverage of the changed files from a profile and
+// works out how much of each file's added code ran. A profile that cannot
+// be read, such as when the build failed, gives no coverage.
+func (m *Model) readCoverage(profile string, pkgs map[string]string, files []git.FileStatus) (map[string]gotest.Coverage, map[string]int) {
+	coverage, err := gotest.ReadCoverage(profile, m.repoPath, pkgs)
+	if err != nil {
+		return nil, nil
+	}
+	percent := make(map[string]int)
+	for _, file := range files {
+		cov, ok := coverage[file.Path]
+		if !ok || !hasWorktreeVersion(file) {
+			continue
+		}
+		added, err := m.gitService.GetAddedLines(file)
+		if err != nil {
+			continue
+		}
+		if p, ok := addedCoverage(cov, added); ok {
+			percent[file.Path] = p
+		}
+	}
+	return coverage, percent
+}
+
+// waitForTestEvent delivers the next message from a test run.
+func waitForTestEvent(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// stopTests cancels a test run in progress.
+func (m *Model) stopTests() {
+	if m.cancelTests != nil {
+		m.cancelTests()
+		m.cancelTests = nil
+	}
+}
+
+// setTestMarks flags the files whose packages have failing tests.
+func (m *Model) setTestMarks() {
+	marks := make(map[string]string)
+	for path, pkg := range m.testFiles {
+		if m.testResults.Failed(pkg) {
+			marks[path] = "fail"
+		}
+	}
+	m.fileList.SetMarks(markTests, marks)
+}
+
+// toggleTests shows or hides the test results pane.
+func (m *Model) toggleTests() {
+	m.showTests = !m.showTests
+	if !m.showTests && m.activePane == PaneTestResults {
+		m.activePane = PaneFileList
+	}
+	m.updateLayout()
+}
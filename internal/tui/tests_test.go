@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"grua/internal/gotest"
+)
+
+func TestTestResultsAddEvent(t *testing.T) {
+	r := NewTestResults(NewStyles(), DefaultKeyMap())
+	r.SetSize(80, 40)
+	r.Start([]string{"m/ok", "m/bad", "m/init"})
+	for _, e := range []gotest.Event{
+		{Action: "output", Package: "m/ok", Test: "TestOK", Output: "=== RUN   TestOK\n"},
+		{Action: "output", Package: "m/ok", Test: "TestOK", Output: "    ok_test.go:5: quiet\n"},
+		{Action: "pass", Package: "m/ok", Test: "TestOK"},
+		{Action: "pass", Package: "m/ok", Elapsed: 0.5},
+		{Action: "output", Package: "m/bad", Test: "TestBad", Output: "=== RUN   TestBad\n"},
+		{Action: "output", Package: "m/bad", Test: "TestBad", Output: "    bad_test.go:5: got 1\n"},
+		{Action: "output", Package: "m/bad", Test: "TestBad", Output: "--- FAIL: TestBad (0.00s)\n"},
+		{Action: "fail", Package: "m/bad", Test: "TestBad"},
+		{Action: "output", Package: "m/bad", Output: "FAIL\n"},
+		{Action: "fail", Package: "m/bad"},
+		{Action: "output", Package: "m/init", Output: "panic: in init\n"},
+		{Action: "fail", Package: "m/init"},
+		{Action: "output", Output: "# m/broken\n"},
+	} {
+		r.AddEvent(e)
+	}
+	r.Finish(nil)
+
+	if r.Running() || r.FailedCount() != 2 || !r.Failed("m/bad") || !r.Failed("m/init") || r.Failed("m/ok") {
+		t.Errorf("running = %v, failed = %v", r.Running(), r.failed)
+	}
+	got := strings.Join(r.lines, "\n")
+	for _, want := range []string{"ok   m/ok 0.50s", "--- FAIL: TestBad", "    bad_test.go:5: got 1", "FAIL m/bad", "panic: in init", "# m/broken"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("results lack %q:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"quiet", "=== RUN"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("results contain %q:\n%s", unwanted, got)
+		}
+	}
+	if n := strings.Count(got, "--- FAIL"); n != 1 {
+		t.Errorf("TestBad's failure is shown %d times:\n%s", n, got)
+	}
+	if len(r.output) != 0 {
+		t.Errorf("output left buffered: %v", r.output)
+	}
+}
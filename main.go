@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"grua/internal/config"
 	"grua/internal/git"
 	"grua/internal/tui"
 
@@ -18,8 +19,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "check":
+			os.Exit(runCheck(repoPath, cfg.Rules))
+		default:
+			fmt.Fprintf(os.Stderr, "Usage: grua [check]\n")
+			os.Exit(2)
+		}
+	}
+
 	// Create and run the TUI
-	model := tui.NewModel(repoPath)
+	model := tui.NewModel(repoPath, cfg)
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	if _, err := p.Run(); err != nil {